@@ -0,0 +1,515 @@
+package fabclient
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Transaction is a decoded transaction of a block.
+type Transaction struct {
+	TxID               string
+	Type               string
+	ChannelID          string
+	Timestamp          time.Time
+	CreatorMSPID       string
+	CreatorCertificate []byte
+	ValidationCode     TxValidationCode
+	Actions            []*TransactionAction
+}
+
+// TransactionAction is a decoded chaincode invocation of an endorser transaction.
+type TransactionAction struct {
+	ChaincodeName    string
+	ChaincodeVersion string
+	Function         string
+	Args             [][]byte
+	Response         *ChaincodeResponse
+	Event            *ChaincodeEvent
+	ReadWriteSets    []*NamespaceReadWriteSet
+}
+
+// ChaincodeResponse is the response returned by the chaincode to the endorsing peers.
+type ChaincodeResponse struct {
+	Status  int32
+	Message string
+	Payload []byte
+}
+
+// NamespaceReadWriteSet holds the public reads and writes of a transaction action for a namespace, as well as the hashes
+// of its private data reads and writes per collection.
+type NamespaceReadWriteSet struct {
+	Namespace        string
+	Reads            []*KVRead
+	Writes           []*KVWrite
+	CollectionHashes []*CollectionHashedReadWriteSet
+}
+
+// KVRead is a key read by a transaction along with the version it had when the transaction was endorsed.
+// The version is nil if the key did not exist.
+type KVRead struct {
+	Key     string
+	Version *KVVersion
+}
+
+// KVWrite is a key written or deleted by a transaction.
+type KVWrite struct {
+	Key      string
+	IsDelete bool
+	Value    []byte
+}
+
+// KVVersion is the version of a key, which is the position of the transaction which wrote it.
+type KVVersion struct {
+	BlockNumber uint64
+	TxNumber    uint64
+}
+
+// CollectionHashedReadWriteSet holds the hashes of the private data reads and writes of a transaction for a collection.
+type CollectionHashedReadWriteSet struct {
+	CollectionName string
+	HashedReads    []*KVReadHash
+	HashedWrites   []*KVWriteHash
+	PvtRwSetHash   []byte
+}
+
+// KVReadHash is the hash of a private data key read by a transaction.
+type KVReadHash struct {
+	KeyHash []byte
+	Version *KVVersion
+}
+
+// KVWriteHash is the hash of a private data key written or deleted by a transaction.
+type KVWriteHash struct {
+	KeyHash   []byte
+	IsDelete  bool
+	ValueHash []byte
+}
+
+// BlockSignature is a signature of the block by an orderer.
+type BlockSignature struct {
+	SignerMSPID       string
+	SignerCertificate []byte
+	Nonce             []byte
+	Signature         []byte
+}
+
+// Transactions decodes the envelopes of the block. Only endorser transactions have actions. The validation code of the
+// transactions is NOT_VALIDATED if the block has not been validated by a peer yet.
+func (b *Block) Transactions() ([]*Transaction, error) {
+	if b.Data == nil {
+		return nil, nil
+	}
+
+	var blockNumber uint64
+	if b.Header != nil {
+		blockNumber = b.Header.Number
+	}
+
+	validationCodes := b.ValidationCodes()
+	transactions := make([]*Transaction, 0, len(b.Data.Data))
+	for txIndex, data := range b.Data.Data {
+		envelope := &common.Envelope{}
+		if err := proto.Unmarshal(data, envelope); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal envelope of transaction %d: %w", txIndex, err)
+		}
+
+		tx, err := decodeTransaction(envelope, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", txIndex, err)
+		}
+
+		tx.ValidationCode = TxValidationCode(peer.TxValidationCode_NOT_VALIDATED)
+		if txIndex < len(validationCodes) {
+			tx.ValidationCode = validationCodes[txIndex]
+		}
+
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+// ValidationCodes returns the validation code of each transaction of the block, in the order of the transactions.
+// It returns nil if the block has not been validated by a peer yet.
+func (b *Block) ValidationCodes() []TxValidationCode {
+	filter := b.metadata(common.BlockMetadataIndex_TRANSACTIONS_FILTER)
+	if len(filter) == 0 {
+		return nil
+	}
+
+	validationCodes := make([]TxValidationCode, 0, len(filter))
+	for _, code := range filter {
+		validationCodes = append(validationCodes, TxValidationCode(code))
+	}
+
+	return validationCodes
+}
+
+// Signatures returns the signatures of the block by the orderers along with the identity of the signers.
+func (b *Block) Signatures() ([]*BlockSignature, error) {
+	metadata, err := b.decodeMetadata(common.BlockMetadataIndex_SIGNATURES)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+
+	signatures := make([]*BlockSignature, 0, len(metadata.Signatures))
+	for _, metadataSignature := range metadata.Signatures {
+		signatureHeader := &common.SignatureHeader{}
+		if err := proto.Unmarshal(metadataSignature.SignatureHeader, signatureHeader); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signature header: %w", err)
+		}
+
+		signer := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(signatureHeader.Creator, signer); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signer: %w", err)
+		}
+
+		signatures = append(signatures, &BlockSignature{
+			SignerMSPID:       signer.Mspid,
+			SignerCertificate: signer.IdBytes,
+			Nonce:             signatureHeader.Nonce,
+			Signature:         metadataSignature.Signature,
+		})
+	}
+
+	return signatures, nil
+}
+
+// LastConfigIndex returns the number of the last configuration block of the channel at the time the block was cut.
+func (b *Block) LastConfigIndex() (uint64, error) {
+	metadata, err := b.decodeMetadata(common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return 0, err
+	}
+
+	if metadata != nil && len(metadata.Value) > 0 {
+		ordererMetadata := &common.OrdererBlockMetadata{}
+		if err := proto.Unmarshal(metadata.Value, ordererMetadata); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal orderer block metadata: %w", err)
+		}
+
+		if ordererMetadata.LastConfig != nil {
+			return ordererMetadata.LastConfig.Index, nil
+		}
+	}
+
+	// blocks cut before Fabric v2.0 record the last config in its own metadata entry
+	metadata, err = b.decodeMetadata(common.BlockMetadataIndex_LAST_CONFIG)
+	if err != nil {
+		return 0, err
+	}
+
+	if metadata == nil {
+		return 0, fmt.Errorf("block has no last config metadata")
+	}
+
+	lastConfig := &common.LastConfig{}
+	if err := proto.Unmarshal(metadata.Value, lastConfig); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal last config: %w", err)
+	}
+
+	return lastConfig.Index, nil
+}
+
+// CommitHash returns the commit hash computed by the peer when committing the block, nil if there is none.
+func (b *Block) CommitHash() ([]byte, error) {
+	metadata, err := b.decodeMetadata(common.BlockMetadataIndex_COMMIT_HASH)
+	if err != nil || metadata == nil {
+		return nil, err
+	}
+
+	return metadata.Value, nil
+}
+
+func (b *Block) metadata(index common.BlockMetadataIndex) []byte {
+	if b.Metadata == nil || len(b.Metadata.Metadata) <= int(index) {
+		return nil
+	}
+
+	return b.Metadata.Metadata[index]
+}
+
+func (b *Block) decodeMetadata(index common.BlockMetadataIndex) (*common.Metadata, error) {
+	value := b.metadata(index)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(value, metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s metadata: %w", index, err)
+	}
+
+	return metadata, nil
+}
+
+func decodeTransaction(envelope *common.Envelope, blockNumber uint64) (*Transaction, error) {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.GetHeader().GetChannelHeader(), channelHeader); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal channel header: %w", err)
+	}
+
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(payload.GetHeader().GetSignatureHeader(), signatureHeader); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signature header: %w", err)
+	}
+
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureHeader.Creator, creator); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal creator: %w", err)
+	}
+
+	tx := &Transaction{
+		TxID:               channelHeader.TxId,
+		Type:               common.HeaderType(channelHeader.Type).String(),
+		ChannelID:          channelHeader.ChannelId,
+		CreatorMSPID:       creator.Mspid,
+		CreatorCertificate: creator.IdBytes,
+	}
+
+	if channelHeader.Timestamp != nil {
+		timestamp, err := ptypes.Timestamp(channelHeader.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert timestamp: %w", err)
+		}
+
+		tx.Timestamp = timestamp
+	}
+
+	if common.HeaderType(channelHeader.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return tx, nil
+	}
+
+	transaction := &peer.Transaction{}
+	if err := proto.Unmarshal(payload.Data, transaction); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+	}
+
+	for actionIndex, action := range transaction.Actions {
+		txAction, err := decodeTransactionAction(action, channelHeader.TxId, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode action %d of transaction '%s': %w", actionIndex, channelHeader.TxId, err)
+		}
+
+		tx.Actions = append(tx.Actions, txAction)
+	}
+
+	return tx, nil
+}
+
+func decodeTransactionAction(action *peer.TransactionAction, txID string, blockNumber uint64) (*TransactionAction, error) {
+	actionPayload := &peer.ChaincodeActionPayload{}
+	if err := proto.Unmarshal(action.Payload, actionPayload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chaincode action payload: %w", err)
+	}
+
+	proposalPayload := &peer.ChaincodeProposalPayload{}
+	if err := proto.Unmarshal(actionPayload.ChaincodeProposalPayload, proposalPayload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chaincode proposal payload: %w", err)
+	}
+
+	invocationSpec := &peer.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(proposalPayload.Input, invocationSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chaincode invocation spec: %w", err)
+	}
+
+	chaincodeAction, err := decodeChaincodeAction(actionPayload.GetAction().GetProposalResponsePayload())
+	if err != nil {
+		return nil, err
+	}
+
+	txAction := &TransactionAction{
+		ChaincodeName:    chaincodeAction.GetChaincodeId().GetName(),
+		ChaincodeVersion: chaincodeAction.GetChaincodeId().GetVersion(),
+	}
+
+	if args := invocationSpec.GetChaincodeSpec().GetInput().GetArgs(); len(args) > 0 {
+		txAction.Function = string(args[0])
+		txAction.Args = args[1:]
+	}
+
+	if response := chaincodeAction.Response; response != nil {
+		txAction.Response = &ChaincodeResponse{
+			Status:  response.Status,
+			Message: response.Message,
+			Payload: response.Payload,
+		}
+	}
+
+	event, err := decodeChaincodeEvent(chaincodeAction.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	if event != nil {
+		event.TxID = txID
+		event.BlockNumber = blockNumber
+		txAction.Event = event
+	}
+
+	if len(chaincodeAction.Results) > 0 {
+		readWriteSets, err := decodeReadWriteSets(chaincodeAction.Results)
+		if err != nil {
+			return nil, err
+		}
+
+		txAction.ReadWriteSets = readWriteSets
+	}
+
+	return txAction, nil
+}
+
+// decodeChaincodeAction decodes the chaincode action, holding the simulation results, of a proposal response payload.
+func decodeChaincodeAction(proposalResponsePayload []byte) (*peer.ChaincodeAction, error) {
+	responsePayload := &peer.ProposalResponsePayload{}
+	if err := proto.Unmarshal(proposalResponsePayload, responsePayload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal proposal response payload: %w", err)
+	}
+
+	chaincodeAction := &peer.ChaincodeAction{}
+	if err := proto.Unmarshal(responsePayload.Extension, chaincodeAction); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chaincode action: %w", err)
+	}
+
+	return chaincodeAction, nil
+}
+
+// decodeChaincodeEvent decodes the chaincode event set by a chaincode action, nil if the chaincode did not set any.
+func decodeChaincodeEvent(events []byte) (*ChaincodeEvent, error) {
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	event := &peer.ChaincodeEvent{}
+	if err := proto.Unmarshal(events, event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chaincode event: %w", err)
+	}
+
+	return &ChaincodeEvent{
+		ChaincodeID: event.ChaincodeId,
+		EventName:   event.EventName,
+		Payload:     event.Payload,
+	}, nil
+}
+
+// decodeProposal decodes the channel header, the signature header and the chaincode specification of a chaincode proposal.
+func decodeProposal(proposal *peer.Proposal) (*common.ChannelHeader, *common.SignatureHeader, *peer.ChaincodeSpec, error) {
+	header := &common.Header{}
+	if err := proto.Unmarshal(proposal.Header, header); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal header: %w", err)
+	}
+
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(header.ChannelHeader, channelHeader); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal channel header: %w", err)
+	}
+
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(header.SignatureHeader, signatureHeader); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal signature header: %w", err)
+	}
+
+	proposalPayload := &peer.ChaincodeProposalPayload{}
+	if err := proto.Unmarshal(proposal.Payload, proposalPayload); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal chaincode proposal payload: %w", err)
+	}
+
+	invocationSpec := &peer.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(proposalPayload.Input, invocationSpec); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal chaincode invocation spec: %w", err)
+	}
+
+	return channelHeader, signatureHeader, invocationSpec.GetChaincodeSpec(), nil
+}
+
+func decodeReadWriteSets(results []byte) ([]*NamespaceReadWriteSet, error) {
+	txReadWriteSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(results, txReadWriteSet); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal read/write set: %w", err)
+	}
+
+	readWriteSets := make([]*NamespaceReadWriteSet, 0, len(txReadWriteSet.NsRwset))
+	for _, nsReadWriteSet := range txReadWriteSet.NsRwset {
+		kvReadWriteSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsReadWriteSet.Rwset, kvReadWriteSet); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal read/write set of namespace '%s': %w", nsReadWriteSet.Namespace, err)
+		}
+
+		readWriteSet := &NamespaceReadWriteSet{
+			Namespace: nsReadWriteSet.Namespace,
+		}
+
+		for _, read := range kvReadWriteSet.Reads {
+			readWriteSet.Reads = append(readWriteSet.Reads, &KVRead{
+				Key:     read.Key,
+				Version: convertKVVersion(read.Version),
+			})
+		}
+
+		for _, write := range kvReadWriteSet.Writes {
+			readWriteSet.Writes = append(readWriteSet.Writes, &KVWrite{
+				Key:      write.Key,
+				IsDelete: write.IsDelete,
+				Value:    write.Value,
+			})
+		}
+
+		for _, collectionHashedReadWriteSet := range nsReadWriteSet.CollectionHashedRwset {
+			hashedReadWriteSet := &kvrwset.HashedRWSet{}
+			if err := proto.Unmarshal(collectionHashedReadWriteSet.HashedRwset, hashedReadWriteSet); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal hashed read/write set of collection '%s' in namespace '%s': %w",
+					collectionHashedReadWriteSet.CollectionName, nsReadWriteSet.Namespace, err)
+			}
+
+			collectionHashes := &CollectionHashedReadWriteSet{
+				CollectionName: collectionHashedReadWriteSet.CollectionName,
+				PvtRwSetHash:   collectionHashedReadWriteSet.PvtRwsetHash,
+			}
+
+			for _, read := range hashedReadWriteSet.HashedReads {
+				collectionHashes.HashedReads = append(collectionHashes.HashedReads, &KVReadHash{
+					KeyHash: read.KeyHash,
+					Version: convertKVVersion(read.Version),
+				})
+			}
+
+			for _, write := range hashedReadWriteSet.HashedWrites {
+				collectionHashes.HashedWrites = append(collectionHashes.HashedWrites, &KVWriteHash{
+					KeyHash:   write.KeyHash,
+					IsDelete:  write.IsDelete,
+					ValueHash: write.ValueHash,
+				})
+			}
+
+			readWriteSet.CollectionHashes = append(readWriteSet.CollectionHashes, collectionHashes)
+		}
+
+		readWriteSets = append(readWriteSets, readWriteSet)
+	}
+
+	return readWriteSets, nil
+}
+
+func convertKVVersion(version *kvrwset.Version) *KVVersion {
+	if version == nil {
+		return nil
+	}
+
+	return &KVVersion{
+		BlockNumber: version.BlockNum,
+		TxNumber:    version.TxNum,
+	}
+}
@@ -0,0 +1,274 @@
+package fabclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	bytes, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bytes
+}
+
+func newTestEnvelope(t *testing.T, headerType common.HeaderType, timestamp time.Time, data []byte) []byte {
+	ts, err := ptypes.TimestampProto(timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: mustMarshal(t, &common.ChannelHeader{
+				Type:      int32(headerType),
+				ChannelId: "mychannel",
+				TxId:      "txid",
+				Timestamp: ts,
+			}),
+			SignatureHeader: mustMarshal(t, &common.SignatureHeader{
+				Creator: mustMarshal(t, &msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("certificate")}),
+			}),
+		},
+		Data: data,
+	}
+
+	return mustMarshal(t, &common.Envelope{Payload: mustMarshal(t, payload)})
+}
+
+func newTestChaincodeAction(t *testing.T) *peer.ChaincodeAction {
+	results := &rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{
+			{
+				Namespace: "mycc",
+				Rwset: mustMarshal(t, &kvrwset.KVRWSet{
+					Reads:  []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: 3, TxNum: 1}}, {Key: "b"}},
+					Writes: []*kvrwset.KVWrite{{Key: "a", Value: []byte("42")}, {Key: "c", IsDelete: true}},
+				}),
+				CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{
+					{
+						CollectionName: "collection",
+						HashedRwset: mustMarshal(t, &kvrwset.HashedRWSet{
+							HashedReads:  []*kvrwset.KVReadHash{{KeyHash: []byte("keyhash")}},
+							HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+						}),
+						PvtRwsetHash: []byte("pvthash"),
+					},
+				},
+			},
+		},
+	}
+
+	return &peer.ChaincodeAction{
+		Results:     mustMarshal(t, results),
+		Events:      mustMarshal(t, &peer.ChaincodeEvent{ChaincodeId: "mycc", TxId: "txid", EventName: "event", Payload: []byte("payload")}),
+		Response:    &peer.Response{Status: 200, Message: "OK", Payload: []byte("result")},
+		ChaincodeId: &peer.ChaincodeID{Name: "mycc", Version: "1.0"},
+	}
+}
+
+func newTestEndorserTransaction(t *testing.T) []byte {
+	actionPayload := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: mustMarshal(t, &peer.ChaincodeProposalPayload{
+			Input: mustMarshal(t, &peer.ChaincodeInvocationSpec{
+				ChaincodeSpec: &peer.ChaincodeSpec{
+					ChaincodeId: &peer.ChaincodeID{Name: "mycc"},
+					Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("Set"), []byte("a"), []byte("42")}},
+				},
+			}),
+		}),
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: mustMarshal(t, &peer.ProposalResponsePayload{Extension: mustMarshal(t, newTestChaincodeAction(t))}),
+		},
+	}
+
+	transaction := &peer.Transaction{
+		Actions: []*peer.TransactionAction{{Payload: mustMarshal(t, actionPayload)}},
+	}
+
+	return mustMarshal(t, transaction)
+}
+
+func TestBlockTransactions(t *testing.T) {
+	timestamp := time.Date(2020, time.October, 1, 12, 0, 0, 0, time.UTC)
+	block := &Block{
+		Header: &BlockHeader{Number: 7},
+		Data: &BlockData{
+			Data: [][]byte{
+				newTestEnvelope(t, common.HeaderType_ENDORSER_TRANSACTION, timestamp, newTestEndorserTransaction(t)),
+				newTestEnvelope(t, common.HeaderType_CONFIG, timestamp, nil),
+			},
+		},
+	}
+
+	transactions, err := block.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transactions) != 2 {
+		t.Fatalf("expected 2 transactions but got %d", len(transactions))
+	}
+
+	tx := transactions[0]
+	if tx.TxID != "txid" || tx.Type != "ENDORSER_TRANSACTION" || tx.ChannelID != "mychannel" || !tx.Timestamp.Equal(timestamp) {
+		t.Errorf("unexpected transaction header: %+v", tx)
+	}
+
+	if tx.ValidationCode.String() != "NOT_VALIDATED" {
+		t.Errorf("transaction of a block not validated yet should be NOT_VALIDATED but got %s", tx.ValidationCode)
+	}
+
+	if tx.CreatorMSPID != "Org1MSP" || string(tx.CreatorCertificate) != "certificate" {
+		t.Errorf("unexpected transaction creator: %s %s", tx.CreatorMSPID, tx.CreatorCertificate)
+	}
+
+	if len(tx.Actions) != 1 {
+		t.Fatalf("expected 1 action but got %d", len(tx.Actions))
+	}
+
+	action := tx.Actions[0]
+	if action.ChaincodeName != "mycc" || action.ChaincodeVersion != "1.0" || action.Function != "Set" || len(action.Args) != 2 {
+		t.Errorf("unexpected chaincode invocation: %+v", action)
+	}
+
+	if action.Response == nil || action.Response.Status != 200 || string(action.Response.Payload) != "result" {
+		t.Errorf("unexpected chaincode response: %+v", action.Response)
+	}
+
+	if action.Event == nil || action.Event.EventName != "event" || action.Event.BlockNumber != 7 || action.Event.TxID != "txid" {
+		t.Errorf("unexpected chaincode event: %+v", action.Event)
+	}
+
+	if len(action.ReadWriteSets) != 1 {
+		t.Fatalf("expected 1 read/write set but got %d", len(action.ReadWriteSets))
+	}
+
+	readWriteSet := action.ReadWriteSets[0]
+	if readWriteSet.Namespace != "mycc" || len(readWriteSet.Reads) != 2 || len(readWriteSet.Writes) != 2 {
+		t.Errorf("unexpected read/write set: %+v", readWriteSet)
+	}
+
+	if version := readWriteSet.Reads[0].Version; version == nil || version.BlockNumber != 3 || version.TxNumber != 1 {
+		t.Errorf("unexpected read version: %+v", version)
+	}
+
+	if readWriteSet.Reads[1].Version != nil || !readWriteSet.Writes[1].IsDelete {
+		t.Errorf("unexpected read/write set: %+v", readWriteSet)
+	}
+
+	if len(readWriteSet.CollectionHashes) != 1 {
+		t.Fatalf("expected 1 collection but got %d", len(readWriteSet.CollectionHashes))
+	}
+
+	collection := readWriteSet.CollectionHashes[0]
+	if collection.CollectionName != "collection" || string(collection.PvtRwSetHash) != "pvthash" ||
+		len(collection.HashedReads) != 1 || len(collection.HashedWrites) != 1 || string(collection.HashedWrites[0].ValueHash) != "valuehash" {
+		t.Errorf("unexpected collection hashes: %+v", collection)
+	}
+
+	if config := transactions[1]; config.Type != "CONFIG" || len(config.Actions) != 0 {
+		t.Errorf("unexpected config transaction: %+v", config)
+	}
+
+	block.Data.Data = append(block.Data.Data, []byte("invalid"))
+	if _, err := block.Transactions(); err == nil {
+		t.Error("should have returned an error when decoding an invalid envelope")
+	}
+}
+
+func TestBlockMetadata(t *testing.T) {
+	block := &Block{
+		Data: &BlockData{
+			Data: [][]byte{newTestEnvelope(t, common.HeaderType_ENDORSER_TRANSACTION, time.Now(), newTestEndorserTransaction(t))},
+		},
+		Metadata: &BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+
+	if block.ValidationCodes() != nil {
+		t.Error("validation codes should be nil when the block has not been validated")
+	}
+
+	if signatures, err := block.Signatures(); err != nil || len(signatures) != 0 {
+		t.Errorf("block should have no signatures but got %v: %v", signatures, err)
+	}
+
+	if _, err := block.LastConfigIndex(); err == nil {
+		t.Error("should have returned an error when the block has no last config metadata")
+	}
+
+	if commitHash, err := block.CommitHash(); err != nil || commitHash != nil {
+		t.Errorf("block should have no commit hash but got %v: %v", commitHash, err)
+	}
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = mustMarshal(t, &common.Metadata{
+		Value: mustMarshal(t, &common.OrdererBlockMetadata{LastConfig: &common.LastConfig{Index: 5}}),
+		Signatures: []*common.MetadataSignature{
+			{
+				SignatureHeader: mustMarshal(t, &common.SignatureHeader{
+					Creator: mustMarshal(t, &msp.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: []byte("certificate")}),
+					Nonce:   []byte("nonce"),
+				}),
+				Signature: []byte("signature"),
+			},
+		},
+	})
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{byte(peer.TxValidationCode_MVCC_READ_CONFLICT)}
+	block.Metadata.Metadata[common.BlockMetadataIndex_COMMIT_HASH] = mustMarshal(t, &common.Metadata{Value: []byte("commithash")})
+
+	validationCodes := block.ValidationCodes()
+	if len(validationCodes) != 1 || validationCodes[0].String() != "MVCC_READ_CONFLICT" {
+		t.Errorf("unexpected validation codes: %v", validationCodes)
+	}
+
+	transactions, err := block.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transactions[0].ValidationCode != validationCodes[0] {
+		t.Errorf("transaction should be %s but got %s", validationCodes[0], transactions[0].ValidationCode)
+	}
+
+	signatures, err := block.Signatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(signatures) != 1 || signatures[0].SignerMSPID != "OrdererMSP" || string(signatures[0].SignerCertificate) != "certificate" ||
+		string(signatures[0].Nonce) != "nonce" || string(signatures[0].Signature) != "signature" {
+		t.Errorf("unexpected signatures: %+v", signatures)
+	}
+
+	if index, err := block.LastConfigIndex(); err != nil || index != 5 {
+		t.Errorf("last config index should be 5 but got %d: %v", index, err)
+	}
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = nil
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = mustMarshal(t, &common.Metadata{
+		Value: mustMarshal(t, &common.LastConfig{Index: 3}),
+	})
+
+	if index, err := block.LastConfigIndex(); err != nil || index != 3 {
+		t.Errorf("last config index should be 3 but got %d: %v", index, err)
+	}
+
+	if commitHash, err := block.CommitHash(); err != nil || string(commitHash) != "commithash" {
+		t.Errorf("unexpected commit hash %s: %v", commitHash, err)
+	}
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_COMMIT_HASH] = []byte("invalid")
+	if _, err := block.CommitHash(); err == nil {
+		t.Error("should have returned an error when decoding invalid metadata")
+	}
+}
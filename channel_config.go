@@ -0,0 +1,446 @@
+package fabclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+const (
+	applicationGroupKey = "Application"
+	ordererGroupKey     = "Orderer"
+
+	aclsKey             = "ACLs"
+	anchorPeersKey      = "AnchorPeers"
+	batchSizeKey        = "BatchSize"
+	batchTimeoutKey     = "BatchTimeout"
+	capabilitiesKey     = "Capabilities"
+	consensusTypeKey    = "ConsensusType"
+	endpointsKey        = "Endpoints"
+	mspKey              = "MSP"
+	ordererAddressesKey = "OrdererAddresses"
+
+	etcdraftConsensusType = "etcdraft"
+)
+
+// ChannelConfig is the configuration of a channel as recorded in its last configuration block.
+type ChannelConfig struct {
+	ChannelID        string
+	BlockNumber      uint64
+	Sequence         uint64
+	OrdererAddresses []string
+	Capabilities     []string
+	Policies         map[string]*Policy
+	Application      *ApplicationConfig
+	Orderer          *OrdererConfig
+}
+
+// ApplicationConfig is the configuration of the organizations of a channel which host peers.
+type ApplicationConfig struct {
+	Organizations []*OrganizationConfig
+	Capabilities  []string
+	ACLs          map[string]string
+	Policies      map[string]*Policy
+}
+
+// OrdererConfig is the configuration of the ordering service of a channel.
+type OrdererConfig struct {
+	OrdererType   string
+	BatchSize     BatchSize
+	BatchTimeout  time.Duration
+	Consenters    []*Consenter
+	Organizations []*OrganizationConfig
+	Capabilities  []string
+	Policies      map[string]*Policy
+}
+
+// BatchSize defines the size of the blocks cut by the ordering service.
+type BatchSize struct {
+	MaxMessageCount   uint32 `json:"maxMessageCount" yaml:"maxMessageCount"`
+	AbsoluteMaxBytes  uint32 `json:"absoluteMaxBytes" yaml:"absoluteMaxBytes"`
+	PreferredMaxBytes uint32 `json:"preferredMaxBytes" yaml:"preferredMaxBytes"`
+}
+
+// Consenter is a member of the consensus of a Raft ordering service.
+type Consenter struct {
+	Host          string
+	Port          uint32
+	ClientTLSCert []byte
+	ServerTLSCert []byte
+}
+
+// OrganizationConfig is the configuration of an organization of a channel. The anchor peers are only set for the
+// application organizations, the endpoints only for the orderer organizations.
+type OrganizationConfig struct {
+	Name              string
+	MSPID             string
+	RootCerts         [][]byte
+	IntermediateCerts [][]byte
+	TLSRootCerts      [][]byte
+	AnchorPeers       []*AnchorPeer
+	Endpoints         []string
+	Policies          map[string]*Policy
+}
+
+// AnchorPeer is a peer of an organization used for cross-organization gossip communication.
+type AnchorPeer struct {
+	Host string `json:"host" yaml:"host"`
+	Port int    `json:"port" yaml:"port"`
+}
+
+// Policy is a policy of the channel configuration. The rule of a signature policy is expressed with the syntax used by
+// Fabric for endorsement policies (i.e. "OutOf(1, 'Org1MSP.member', 'Org2MSP.member')"), the one of an implicit meta
+// policy as "<ANY|ALL|MAJORITY> <sub policy>".
+type Policy struct {
+	Type      string `json:"type" yaml:"type"`
+	Rule      string `json:"rule" yaml:"rule"`
+	ModPolicy string `json:"modPolicy,omitempty" yaml:"modPolicy,omitempty"`
+}
+
+func extractConfigFromBlock(block *common.Block) (*common.Config, error) {
+	if len(block.GetData().GetData()) == 0 {
+		return nil, fmt.Errorf("config block is empty")
+	}
+
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(block.Data.Data[0], envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	configEnvelope := &common.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config envelope: %w", err)
+	}
+
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, fmt.Errorf("config envelope has no channel group")
+	}
+
+	return configEnvelope.Config, nil
+}
+
+func decodeChannelConfig(channelID string, blockNumber uint64, config *common.Config) (*ChannelConfig, error) {
+	channelGroup := config.ChannelGroup
+	channelConfig := &ChannelConfig{
+		ChannelID:   channelID,
+		BlockNumber: blockNumber,
+		Sequence:    config.Sequence,
+	}
+
+	ordererAddresses := &common.OrdererAddresses{}
+	if err := unmarshalConfigValue(channelGroup, ordererAddressesKey, ordererAddresses); err != nil {
+		return nil, err
+	}
+	channelConfig.OrdererAddresses = ordererAddresses.Addresses
+
+	var err error
+	if channelConfig.Capabilities, err = decodeCapabilities(channelGroup); err != nil {
+		return nil, err
+	}
+
+	if channelConfig.Policies, err = decodePolicies(channelGroup); err != nil {
+		return nil, err
+	}
+
+	if group, ok := channelGroup.Groups[applicationGroupKey]; ok {
+		if channelConfig.Application, err = decodeApplicationConfig(group); err != nil {
+			return nil, fmt.Errorf("failed to decode application config: %w", err)
+		}
+	}
+
+	if group, ok := channelGroup.Groups[ordererGroupKey]; ok {
+		if channelConfig.Orderer, err = decodeOrdererConfig(group); err != nil {
+			return nil, fmt.Errorf("failed to decode orderer config: %w", err)
+		}
+	}
+
+	return channelConfig, nil
+}
+
+func decodeApplicationConfig(group *common.ConfigGroup) (*ApplicationConfig, error) {
+	applicationConfig := &ApplicationConfig{
+		ACLs: make(map[string]string),
+	}
+
+	var err error
+	if applicationConfig.Organizations, err = decodeOrganizations(group); err != nil {
+		return nil, err
+	}
+
+	if applicationConfig.Capabilities, err = decodeCapabilities(group); err != nil {
+		return nil, err
+	}
+
+	if applicationConfig.Policies, err = decodePolicies(group); err != nil {
+		return nil, err
+	}
+
+	acls := &peer.ACLs{}
+	if err := unmarshalConfigValue(group, aclsKey, acls); err != nil {
+		return nil, err
+	}
+
+	for resource, apiResource := range acls.Acls {
+		applicationConfig.ACLs[resource] = apiResource.GetPolicyRef()
+	}
+
+	return applicationConfig, nil
+}
+
+func decodeOrdererConfig(group *common.ConfigGroup) (*OrdererConfig, error) {
+	ordererConfig := &OrdererConfig{}
+
+	var err error
+	if ordererConfig.Organizations, err = decodeOrganizations(group); err != nil {
+		return nil, err
+	}
+
+	if ordererConfig.Capabilities, err = decodeCapabilities(group); err != nil {
+		return nil, err
+	}
+
+	if ordererConfig.Policies, err = decodePolicies(group); err != nil {
+		return nil, err
+	}
+
+	batchSize := &orderer.BatchSize{}
+	if err := unmarshalConfigValue(group, batchSizeKey, batchSize); err != nil {
+		return nil, err
+	}
+
+	ordererConfig.BatchSize = BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	}
+
+	batchTimeout := &orderer.BatchTimeout{}
+	if err := unmarshalConfigValue(group, batchTimeoutKey, batchTimeout); err != nil {
+		return nil, err
+	}
+
+	if len(batchTimeout.Timeout) > 0 {
+		if ordererConfig.BatchTimeout, err = time.ParseDuration(batchTimeout.Timeout); err != nil {
+			return nil, fmt.Errorf("failed to parse batch timeout: %w", err)
+		}
+	}
+
+	consensusType := &orderer.ConsensusType{}
+	if err := unmarshalConfigValue(group, consensusTypeKey, consensusType); err != nil {
+		return nil, err
+	}
+
+	ordererConfig.OrdererType = consensusType.Type
+	if consensusType.Type == etcdraftConsensusType {
+		metadata := &etcdraft.ConfigMetadata{}
+		if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal etcdraft metadata: %w", err)
+		}
+
+		for _, consenter := range metadata.Consenters {
+			ordererConfig.Consenters = append(ordererConfig.Consenters, &Consenter{
+				Host:          consenter.Host,
+				Port:          consenter.Port,
+				ClientTLSCert: consenter.ClientTlsCert,
+				ServerTLSCert: consenter.ServerTlsCert,
+			})
+		}
+	}
+
+	return ordererConfig, nil
+}
+
+func decodeOrganizations(group *common.ConfigGroup) ([]*OrganizationConfig, error) {
+	names := make([]string, 0, len(group.Groups))
+	for name := range group.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	organizations := make([]*OrganizationConfig, 0, len(group.Groups))
+	for _, name := range names {
+		organization, err := decodeOrganization(name, group.Groups[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode organization '%s': %w", name, err)
+		}
+
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, nil
+}
+
+func decodeOrganization(name string, group *common.ConfigGroup) (*OrganizationConfig, error) {
+	organization := &OrganizationConfig{
+		Name: name,
+	}
+
+	fabricMSPConfig, err := decodeFabricMSPConfig(group)
+	if err != nil {
+		return nil, err
+	}
+
+	organization.MSPID = fabricMSPConfig.Name
+	organization.RootCerts = fabricMSPConfig.RootCerts
+	organization.IntermediateCerts = fabricMSPConfig.IntermediateCerts
+	organization.TLSRootCerts = fabricMSPConfig.TlsRootCerts
+
+	anchorPeers := &peer.AnchorPeers{}
+	if err := unmarshalConfigValue(group, anchorPeersKey, anchorPeers); err != nil {
+		return nil, err
+	}
+
+	for _, anchorPeer := range anchorPeers.AnchorPeers {
+		organization.AnchorPeers = append(organization.AnchorPeers, &AnchorPeer{
+			Host: anchorPeer.Host,
+			Port: int(anchorPeer.Port),
+		})
+	}
+
+	endpoints := &common.OrdererAddresses{}
+	if err := unmarshalConfigValue(group, endpointsKey, endpoints); err != nil {
+		return nil, err
+	}
+	organization.Endpoints = endpoints.Addresses
+
+	if organization.Policies, err = decodePolicies(group); err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func decodeCapabilities(group *common.ConfigGroup) ([]string, error) {
+	capabilities := &common.Capabilities{}
+	if err := unmarshalConfigValue(group, capabilitiesKey, capabilities); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(capabilities.Capabilities))
+	for name := range capabilities.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func decodePolicies(group *common.ConfigGroup) (map[string]*Policy, error) {
+	policies := make(map[string]*Policy, len(group.Policies))
+	for name, configPolicy := range group.Policies {
+		policy, err := decodePolicy(configPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode policy '%s': %w", name, err)
+		}
+
+		policies[name] = policy
+	}
+
+	return policies, nil
+}
+
+func decodePolicy(configPolicy *common.ConfigPolicy) (*Policy, error) {
+	policyType := common.Policy_PolicyType(configPolicy.GetPolicy().GetType())
+	policy := &Policy{
+		Type:      policyType.String(),
+		ModPolicy: configPolicy.ModPolicy,
+	}
+
+	switch policyType {
+	case common.Policy_SIGNATURE:
+		signaturePolicy := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, signaturePolicy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signature policy: %w", err)
+		}
+
+		rule, err := signaturePolicyRule(signaturePolicy.Rule, signaturePolicy.Identities)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.Rule = rule
+	case common.Policy_IMPLICIT_META:
+		implicitMetaPolicy := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(configPolicy.Policy.Value, implicitMetaPolicy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal implicit meta policy: %w", err)
+		}
+
+		policy.Rule = fmt.Sprintf("%s %s", implicitMetaPolicy.Rule, implicitMetaPolicy.SubPolicy)
+	}
+
+	return policy, nil
+}
+
+func signaturePolicyRule(rule *common.SignaturePolicy, identities []*msp.MSPPrincipal) (string, error) {
+	switch t := rule.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if int(t.SignedBy) >= len(identities) {
+			return "", fmt.Errorf("signature policy references unknown identity %d", t.SignedBy)
+		}
+
+		return principalRule(identities[t.SignedBy])
+	case *common.SignaturePolicy_NOutOf_:
+		rules := make([]string, 0, len(t.NOutOf.Rules)+1)
+		rules = append(rules, fmt.Sprint(t.NOutOf.N))
+		for _, subRule := range t.NOutOf.Rules {
+			r, err := signaturePolicyRule(subRule, identities)
+			if err != nil {
+				return "", err
+			}
+
+			rules = append(rules, r)
+		}
+
+		return fmt.Sprintf("OutOf(%s)", strings.Join(rules, ", ")), nil
+	default:
+		return "", fmt.Errorf("unknown signature policy rule type %T", t)
+	}
+}
+
+func principalRule(principal *msp.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return "", fmt.Errorf("failed to unmarshal MSP role: %w", err)
+		}
+
+		return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		unit := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, unit); err != nil {
+			return "", fmt.Errorf("failed to unmarshal organization unit: %w", err)
+		}
+
+		return fmt.Sprintf("'%s.%s'", unit.MspIdentifier, unit.OrganizationalUnitIdentifier), nil
+	default:
+		return fmt.Sprintf("'%s'", principal.PrincipalClassification), nil
+	}
+}
+
+// unmarshalConfigValue leaves the message untouched if the group does not hold the value.
+func unmarshalConfigValue(group *common.ConfigGroup, key string, message proto.Message) error {
+	value, ok := group.Values[key]
+	if !ok {
+		return nil
+	}
+
+	if err := proto.Unmarshal(value.Value, message); err != nil {
+		return fmt.Errorf("failed to unmarshal config value '%s': %w", key, err)
+	}
+
+	return nil
+}
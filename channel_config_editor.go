@@ -0,0 +1,395 @@
+package fabclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/policydsl"
+)
+
+// ChannelConfigEditor modifies the configuration of a channel. It is handed over to the edit function given to
+// Client.UpdateChannelConfig, the config update submitted is the delta between the current channel configuration and
+// the edited one.
+//
+// Groups are addressed by their path from the channel group, their keys being separated by a slash
+// (i.e. "" for the channel group, "Application", "Application/Org1MSP", "Orderer/OrdererOrg"). The key of an
+// organization group is its name, which does not necessarily match its MSP ID.
+type ChannelConfigEditor struct {
+	config *common.Config
+}
+
+func newChannelConfigEditor(config *common.Config) *ChannelConfigEditor {
+	return &ChannelConfigEditor{config: config}
+}
+
+// AddOrganization adds an organization to the application group of the channel. The organization is named after its
+// MSP ID if no name is provided. Its MSP enables node OUs, identities being classified by the "client", "peer", "admin"
+// and "orderer" organizational units issued by its first root certificate. If no policies are provided, the Readers,
+// Writers, Admins and Endorsement policies of the organization are defined from its roles.
+func (editor *ChannelConfigEditor) AddOrganization(organization *OrganizationConfig) error {
+	if organization == nil || organization.MSPID == "" {
+		return fmt.Errorf("failed to add organization: MSP ID is required")
+	}
+
+	if len(organization.RootCerts) == 0 {
+		return fmt.Errorf("failed to add organization '%s': at least one root certificate is required", organization.MSPID)
+	}
+
+	application, err := editor.group(applicationGroupKey)
+	if err != nil {
+		return err
+	}
+
+	name := organization.Name
+	if name == "" {
+		name = organization.MSPID
+	}
+
+	if _, ok := application.Groups[name]; ok {
+		return fmt.Errorf("failed to add organization: organization '%s' already exists", name)
+	}
+
+	if _, err := findApplicationOrganization(editor.config, organization.MSPID); err == nil {
+		return fmt.Errorf("failed to add organization: organization with MSP ID '%s' already exists", organization.MSPID)
+	}
+
+	group, err := newOrganizationGroup(organization)
+	if err != nil {
+		return fmt.Errorf("failed to add organization '%s': %w", organization.MSPID, err)
+	}
+
+	if application.Groups == nil {
+		application.Groups = make(map[string]*common.ConfigGroup)
+	}
+
+	application.Groups[name] = group
+
+	return nil
+}
+
+// RemoveOrganization removes the organization identified by the given MSP ID from the application group of the channel.
+func (editor *ChannelConfigEditor) RemoveOrganization(mspID string) error {
+	application, err := editor.group(applicationGroupKey)
+	if err != nil {
+		return err
+	}
+
+	for name, organization := range application.Groups {
+		if organization == nil {
+			continue
+		}
+
+		fabricMSPConfig, err := decodeFabricMSPConfig(organization)
+		if err != nil {
+			return err
+		}
+
+		if fabricMSPConfig.Name == mspID {
+			delete(application.Groups, name)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to remove organization: organization '%s' not found in channel application group", mspID)
+}
+
+// SetAnchorPeers sets the anchor peers of the application organization identified by the given MSP ID.
+func (editor *ChannelConfigEditor) SetAnchorPeers(mspID string, anchorPeers []*AnchorPeer) error {
+	return setAnchorPeers(editor.config, mspID, anchorPeers)
+}
+
+// SetPolicy sets a policy of the group at the given path. The mod policy defaults to "Admins".
+func (editor *ChannelConfigEditor) SetPolicy(groupPath, name string, policy *Policy) error {
+	group, err := editor.group(groupPath)
+	if err != nil {
+		return err
+	}
+
+	configPolicy, err := encodePolicy(policy)
+	if err != nil {
+		return fmt.Errorf("failed to set policy '%s': %w", name, err)
+	}
+
+	if group.Policies == nil {
+		group.Policies = make(map[string]*common.ConfigPolicy)
+	}
+
+	group.Policies[name] = configPolicy
+
+	return nil
+}
+
+// RemovePolicy removes a policy of the group at the given path.
+func (editor *ChannelConfigEditor) RemovePolicy(groupPath, name string) error {
+	group, err := editor.group(groupPath)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := group.Policies[name]; !ok {
+		return fmt.Errorf("failed to remove policy: policy '%s' not found in group '%s'", name, groupPath)
+	}
+
+	delete(group.Policies, name)
+
+	return nil
+}
+
+// SetBatchSize sets the size of the blocks cut by the ordering service.
+func (editor *ChannelConfigEditor) SetBatchSize(batchSize BatchSize) error {
+	group, err := editor.group(ordererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setConfigValue(group, batchSizeKey, &orderer.BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	})
+}
+
+// SetBatchTimeout sets the amount of time the ordering service waits before cutting a block.
+func (editor *ChannelConfigEditor) SetBatchTimeout(batchTimeout time.Duration) error {
+	if batchTimeout <= 0 {
+		return fmt.Errorf("failed to set batch timeout: timeout must be positive")
+	}
+
+	group, err := editor.group(ordererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setConfigValue(group, batchTimeoutKey, &orderer.BatchTimeout{Timeout: batchTimeout.String()})
+}
+
+// SetCapabilities sets the capabilities of the group at the given path (i.e. "" for the channel capabilities,
+// "Application" or "Orderer").
+func (editor *ChannelConfigEditor) SetCapabilities(groupPath string, capabilities []string) error {
+	group, err := editor.group(groupPath)
+	if err != nil {
+		return err
+	}
+
+	value := &common.Capabilities{Capabilities: make(map[string]*common.Capability, len(capabilities))}
+	for _, capability := range capabilities {
+		value.Capabilities[capability] = &common.Capability{}
+	}
+
+	return setConfigValue(group, capabilitiesKey, value)
+}
+
+// SetACL sets the policy reference of an ACL resource of the application (i.e. "qscc/GetBlockByNumber" mapped to
+// "/Channel/Application/Readers").
+func (editor *ChannelConfigEditor) SetACL(resource, policyRef string) error {
+	group, err := editor.group(applicationGroupKey)
+	if err != nil {
+		return err
+	}
+
+	acls := &peer.ACLs{}
+	if err := unmarshalConfigValue(group, aclsKey, acls); err != nil {
+		return err
+	}
+
+	if acls.Acls == nil {
+		acls.Acls = make(map[string]*peer.APIResource)
+	}
+
+	acls.Acls[resource] = &peer.APIResource{PolicyRef: policyRef}
+
+	return setConfigValue(group, aclsKey, acls)
+}
+
+// RemoveACL removes an ACL resource of the application, the resource then falls back to its default policy.
+func (editor *ChannelConfigEditor) RemoveACL(resource string) error {
+	group, err := editor.group(applicationGroupKey)
+	if err != nil {
+		return err
+	}
+
+	acls := &peer.ACLs{}
+	if err := unmarshalConfigValue(group, aclsKey, acls); err != nil {
+		return err
+	}
+
+	if _, ok := acls.Acls[resource]; !ok {
+		return fmt.Errorf("failed to remove ACL: resource '%s' not found", resource)
+	}
+
+	delete(acls.Acls, resource)
+
+	return setConfigValue(group, aclsKey, acls)
+}
+
+func (editor *ChannelConfigEditor) group(groupPath string) (*common.ConfigGroup, error) {
+	group := editor.config.GetChannelGroup()
+	if group == nil {
+		return nil, fmt.Errorf("channel config has no channel group")
+	}
+
+	if groupPath == "" {
+		return group, nil
+	}
+
+	for _, key := range strings.Split(groupPath, "/") {
+		if group = group.Groups[key]; group == nil {
+			return nil, fmt.Errorf("group '%s' not found in channel config", groupPath)
+		}
+	}
+
+	return group, nil
+}
+
+func newOrganizationGroup(organization *OrganizationConfig) (*common.ConfigGroup, error) {
+	mspID := organization.MSPID
+	rootCert := organization.RootCerts[0]
+
+	fabricMSPConfig := &msp.FabricMSPConfig{
+		Name:              mspID,
+		RootCerts:         organization.RootCerts,
+		IntermediateCerts: organization.IntermediateCerts,
+		TlsRootCerts:      organization.TLSRootCerts,
+		CryptoConfig: &msp.FabricCryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+		FabricNodeOus: &msp.FabricNodeOUs{
+			Enable:              true,
+			ClientOuIdentifier:  &msp.FabricOUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "client"},
+			PeerOuIdentifier:    &msp.FabricOUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "peer"},
+			AdminOuIdentifier:   &msp.FabricOUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "admin"},
+			OrdererOuIdentifier: &msp.FabricOUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "orderer"},
+		},
+	}
+
+	fabricMSPConfigBytes, err := proto.Marshal(fabricMSPConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	group := newConfigGroup()
+	group.ModPolicy = adminsPolicyKey
+
+	if err := setConfigValue(group, mspKey, &msp.MSPConfig{Config: fabricMSPConfigBytes}); err != nil {
+		return nil, err
+	}
+
+	if len(organization.AnchorPeers) > 0 {
+		anchorPeers := &peer.AnchorPeers{AnchorPeers: make([]*peer.AnchorPeer, len(organization.AnchorPeers))}
+		for i, anchorPeer := range organization.AnchorPeers {
+			anchorPeers.AnchorPeers[i] = &peer.AnchorPeer{Host: anchorPeer.Host, Port: int32(anchorPeer.Port)}
+		}
+
+		if err := setConfigValue(group, anchorPeersKey, anchorPeers); err != nil {
+			return nil, err
+		}
+	}
+
+	policies := organization.Policies
+	if len(policies) == 0 {
+		policies = defaultApplicationOrganizationPolicies(mspID)
+	}
+
+	for name, policy := range policies {
+		if group.Policies[name], err = encodePolicy(policy); err != nil {
+			return nil, fmt.Errorf("invalid policy '%s': %w", name, err)
+		}
+	}
+
+	return group, nil
+}
+
+func newConfigGroup() *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Groups:   make(map[string]*common.ConfigGroup),
+		Values:   make(map[string]*common.ConfigValue),
+		Policies: make(map[string]*common.ConfigPolicy),
+	}
+}
+
+func defaultApplicationOrganizationPolicies(mspID string) map[string]*Policy {
+	return map[string]*Policy{
+		"Readers":     {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.peer', '%[1]s.client')", mspID)},
+		"Writers":     {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.client')", mspID)},
+		"Admins":      {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+		"Endorsement": {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%s.peer')", mspID)},
+	}
+}
+
+func encodePolicy(policy *Policy) (*common.ConfigPolicy, error) {
+	if policy == nil {
+		return nil, fmt.Errorf("policy is nil")
+	}
+
+	modPolicy := policy.ModPolicy
+	if modPolicy == "" {
+		modPolicy = adminsPolicyKey
+	}
+
+	var (
+		policyType common.Policy_PolicyType
+		value      proto.Message
+	)
+
+	switch policy.Type {
+	case common.Policy_SIGNATURE.String():
+		signaturePolicy, err := policydsl.FromString(policy.Rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature policy rule '%s': %w", policy.Rule, err)
+		}
+
+		policyType, value = common.Policy_SIGNATURE, signaturePolicy
+	case common.Policy_IMPLICIT_META.String():
+		fields := strings.Fields(policy.Rule)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid implicit meta policy rule '%s'", policy.Rule)
+		}
+
+		rule, ok := common.ImplicitMetaPolicy_Rule_value[fields[0]]
+		if !ok {
+			return nil, fmt.Errorf("invalid implicit meta policy rule '%s'", policy.Rule)
+		}
+
+		policyType, value = common.Policy_IMPLICIT_META, &common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_Rule(rule), SubPolicy: fields[1]}
+	default:
+		return nil, fmt.Errorf("unsupported policy type '%s'", policy.Type)
+	}
+
+	valueBytes, err := proto.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ConfigPolicy{
+		Policy:    &common.Policy{Type: int32(policyType), Value: valueBytes},
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+// setConfigValue sets a value of a group, an existing value keeps its mod policy.
+func setConfigValue(group *common.ConfigGroup, key string, message proto.Message) error {
+	valueBytes, err := proto.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config value '%s': %w", key, err)
+	}
+
+	if group.Values == nil {
+		group.Values = make(map[string]*common.ConfigValue)
+	}
+
+	modPolicy := adminsPolicyKey
+	if value, ok := group.Values[key]; ok && value.ModPolicy != "" {
+		modPolicy = value.ModPolicy
+	}
+
+	group.Values[key] = &common.ConfigValue{Value: valueBytes, ModPolicy: modPolicy}
+
+	return nil
+}
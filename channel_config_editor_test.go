@@ -0,0 +1,138 @@
+package fabclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChannelConfigEditor(t *testing.T) {
+	block := newTestConfigBlock(t)
+
+	config, err := extractConfigFromBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editor := newChannelConfigEditor(config)
+
+	if err := editor.AddOrganization(&OrganizationConfig{MSPID: "Org3MSP", RootCerts: [][]byte{[]byte("root")}, AnchorPeers: []*AnchorPeer{{Host: "peer0.org3.dummy.com", Port: 11051}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.AddOrganization(&OrganizationConfig{MSPID: "Org3MSP", RootCerts: [][]byte{[]byte("root")}}); err == nil {
+		t.Error("should have returned an error, organization 'Org3MSP' already exists")
+	}
+
+	if err := editor.AddOrganization(&OrganizationConfig{MSPID: "Org4MSP"}); err == nil {
+		t.Error("should have returned an error, organization has no root certificate")
+	}
+
+	if err := editor.RemoveOrganization("Org2MSP"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.RemoveOrganization("Org2MSP"); err == nil {
+		t.Error("should have returned an error, organization 'Org2MSP' has already been removed")
+	}
+
+	if err := editor.SetPolicy("Application", "Admins", &Policy{Type: "IMPLICIT_META", Rule: "ANY Admins"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetPolicy("Application/Org1MSP", "Writers", &Policy{Type: "SIGNATURE", Rule: "OR('Org1MSP.admin', 'Org1MSP.client')"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetPolicy("Application/Org1MSP", "Writers", &Policy{Type: "SIGNATURE", Rule: "invalid"}); err == nil {
+		t.Error("should have returned an error, signature policy rule is invalid")
+	}
+
+	if err := editor.SetPolicy("Application", "Admins", &Policy{Type: "IMPLICIT_META", Rule: "SOME Admins"}); err == nil {
+		t.Error("should have returned an error, implicit meta policy rule is invalid")
+	}
+
+	if err := editor.SetPolicy("Dummy", "Admins", &Policy{Type: "IMPLICIT_META", Rule: "ANY Admins"}); err == nil {
+		t.Error("should have returned an error, group 'Dummy' does not exist")
+	}
+
+	if err := editor.RemovePolicy("Application/Org1MSP", "Readers"); err == nil {
+		t.Error("should have returned an error, policy 'Readers' does not exist")
+	}
+
+	if err := editor.SetBatchSize(BatchSize{MaxMessageCount: 20, AbsoluteMaxBytes: 99, PreferredMaxBytes: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetBatchTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetCapabilities("", []string{"V2_0", "V3_0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.SetACL("qscc/GetChainInfo", "/Channel/Application/Writers"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.RemoveACL("qscc/GetBlockByNumber"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := editor.RemoveACL("qscc/GetBlockByNumber"); err == nil {
+		t.Error("should have returned an error, ACL 'qscc/GetBlockByNumber' has already been removed")
+	}
+
+	channelConfig, err := decodeChannelConfig("channelall", block.Header.Number, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	application := channelConfig.Application
+	if len(application.Organizations) != 2 || application.Organizations[0].MSPID != "Org1MSP" || application.Organizations[1].MSPID != "Org3MSP" {
+		t.Fatalf("unexpected organizations: %+v", application.Organizations)
+	}
+
+	org3 := application.Organizations[1]
+	if len(org3.AnchorPeers) != 1 || org3.AnchorPeers[0].Port != 11051 || len(org3.Policies) != 4 {
+		t.Errorf("unexpected organization: %+v", org3)
+	}
+
+	if policy := org3.Policies["Endorsement"]; policy == nil || policy.Rule != "OutOf(1, 'Org3MSP.peer')" || policy.ModPolicy != "Admins" {
+		t.Errorf("unexpected endorsement policy: %+v", policy)
+	}
+
+	if policy := application.Organizations[0].Policies["Writers"]; policy == nil || policy.Rule != "OutOf(1, 'Org1MSP.admin', 'Org1MSP.client')" {
+		t.Errorf("unexpected writers policy: %+v", policy)
+	}
+
+	if policy := application.Policies["Admins"]; policy == nil || policy.Rule != "ANY Admins" {
+		t.Errorf("unexpected admins policy: %+v", policy)
+	}
+
+	if len(application.ACLs) != 1 || application.ACLs["qscc/GetChainInfo"] != "/Channel/Application/Writers" {
+		t.Errorf("unexpected ACLs: %v", application.ACLs)
+	}
+
+	if channelConfig.Orderer.BatchSize.MaxMessageCount != 20 || channelConfig.Orderer.BatchTimeout != time.Second {
+		t.Errorf("unexpected orderer config: %+v", channelConfig.Orderer)
+	}
+
+	if len(channelConfig.Capabilities) != 2 || channelConfig.Capabilities[1] != "V3_0" {
+		t.Errorf("unexpected capabilities: %v", channelConfig.Capabilities)
+	}
+
+	original, err := extractConfigFromBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configUpdate, err := computeConfigUpdate("channelall", original, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if application := configUpdate.WriteSet.Groups[applicationGroupKey]; application == nil || application.Version != 1 || application.Groups["Org3MSP"] == nil {
+		t.Errorf("unexpected application write set: %+v", application)
+	}
+}
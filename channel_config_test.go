@@ -0,0 +1,190 @@
+package fabclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+func newTestOrganizationGroup(t *testing.T, mspID string) *common.ConfigGroup {
+	mspConfig := &msp.MSPConfig{
+		Config: mustMarshal(t, &msp.FabricMSPConfig{
+			Name:         mspID,
+			RootCerts:    [][]byte{[]byte("root")},
+			TlsRootCerts: [][]byte{[]byte("tlsroot")},
+		}),
+	}
+
+	signaturePolicy := &common.SignaturePolicyEnvelope{
+		Rule: &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_NOutOf_{
+				NOutOf: &common.SignaturePolicy_NOutOf{
+					N: 1,
+					Rules: []*common.SignaturePolicy{
+						{Type: &common.SignaturePolicy_SignedBy{SignedBy: 0}},
+						{Type: &common.SignaturePolicy_SignedBy{SignedBy: 1}},
+					},
+				},
+			},
+		},
+		Identities: []*msp.MSPPrincipal{
+			{
+				PrincipalClassification: msp.MSPPrincipal_ROLE,
+				Principal:               mustMarshal(t, &msp.MSPRole{MspIdentifier: mspID, Role: msp.MSPRole_ADMIN}),
+			},
+			{
+				PrincipalClassification: msp.MSPPrincipal_ROLE,
+				Principal:               mustMarshal(t, &msp.MSPRole{MspIdentifier: mspID, Role: msp.MSPRole_PEER}),
+			},
+		},
+	}
+
+	return &common.ConfigGroup{
+		Values: map[string]*common.ConfigValue{mspKey: {Value: mustMarshal(t, mspConfig), ModPolicy: "Admins"}},
+		Policies: map[string]*common.ConfigPolicy{
+			"Writers": {
+				Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: mustMarshal(t, signaturePolicy)},
+				ModPolicy: "Admins",
+			},
+		},
+	}
+}
+
+func newTestConfigBlock(t *testing.T) *common.Block {
+	org1 := newTestOrganizationGroup(t, "Org1MSP")
+	org1.Values[anchorPeersKey] = &common.ConfigValue{
+		Value: mustMarshal(t, &peer.AnchorPeers{AnchorPeers: []*peer.AnchorPeer{{Host: "peer0.org1.dummy.com", Port: 7051}}}),
+	}
+
+	ordererOrg := newTestOrganizationGroup(t, "OrdererMSP")
+	ordererOrg.Values[endpointsKey] = &common.ConfigValue{
+		Value: mustMarshal(t, &common.OrdererAddresses{Addresses: []string{"orderer.dummy.com:7050"}}),
+	}
+
+	application := &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{"Org1MSP": org1, "Org2MSP": newTestOrganizationGroup(t, "Org2MSP")},
+		Values: map[string]*common.ConfigValue{
+			aclsKey:         {Value: mustMarshal(t, &peer.ACLs{Acls: map[string]*peer.APIResource{"qscc/GetBlockByNumber": {PolicyRef: "/Channel/Application/Readers"}}})},
+			capabilitiesKey: {Value: mustMarshal(t, &common.Capabilities{Capabilities: map[string]*common.Capability{"V2_0": {}}})},
+		},
+		Policies: map[string]*common.ConfigPolicy{
+			"Admins": {
+				Policy: &common.Policy{
+					Type:  int32(common.Policy_IMPLICIT_META),
+					Value: mustMarshal(t, &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY}),
+				},
+				ModPolicy: "Admins",
+			},
+		},
+	}
+
+	consensusType := &orderer.ConsensusType{
+		Type: etcdraftConsensusType,
+		Metadata: mustMarshal(t, &etcdraft.ConfigMetadata{
+			Consenters: []*etcdraft.Consenter{{Host: "orderer.dummy.com", Port: 7050, ClientTlsCert: []byte("client"), ServerTlsCert: []byte("server")}},
+		}),
+	}
+
+	ordererGroup := &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{"OrdererOrg": ordererOrg},
+		Values: map[string]*common.ConfigValue{
+			batchSizeKey:     {Value: mustMarshal(t, &orderer.BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 99, PreferredMaxBytes: 42})},
+			batchTimeoutKey:  {Value: mustMarshal(t, &orderer.BatchTimeout{Timeout: "2s"})},
+			consensusTypeKey: {Value: mustMarshal(t, consensusType)},
+		},
+	}
+
+	config := &common.Config{
+		Sequence: 3,
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{applicationGroupKey: application, ordererGroupKey: ordererGroup},
+			Values: map[string]*common.ConfigValue{
+				ordererAddressesKey: {Value: mustMarshal(t, &common.OrdererAddresses{Addresses: []string{"orderer.dummy.com:7050"}})},
+				capabilitiesKey:     {Value: mustMarshal(t, &common.Capabilities{Capabilities: map[string]*common.Capability{"V2_0": {}}})},
+			},
+		},
+	}
+
+	payload := &common.Payload{
+		Data: mustMarshal(t, &common.ConfigEnvelope{Config: config}),
+	}
+
+	return &common.Block{
+		Header: &common.BlockHeader{Number: 2},
+		Data:   &common.BlockData{Data: [][]byte{mustMarshal(t, &common.Envelope{Payload: mustMarshal(t, payload)})}},
+	}
+}
+
+func TestDecodeChannelConfig(t *testing.T) {
+	block := newTestConfigBlock(t)
+
+	config, err := extractConfigFromBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelConfig, err := decodeChannelConfig("channelall", block.Header.Number, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if channelConfig.ChannelID != "channelall" || channelConfig.BlockNumber != 2 || channelConfig.Sequence != 3 {
+		t.Errorf("unexpected channel config: %+v", channelConfig)
+	}
+
+	if len(channelConfig.OrdererAddresses) != 1 || len(channelConfig.Capabilities) != 1 || channelConfig.Capabilities[0] != "V2_0" {
+		t.Errorf("unexpected channel values: %+v", channelConfig)
+	}
+
+	application := channelConfig.Application
+	if application == nil || len(application.Organizations) != 2 {
+		t.Fatalf("unexpected application config: %+v", application)
+	}
+
+	if application.ACLs["qscc/GetBlockByNumber"] != "/Channel/Application/Readers" {
+		t.Errorf("unexpected ACLs: %v", application.ACLs)
+	}
+
+	if policy := application.Policies["Admins"]; policy == nil || policy.Type != "IMPLICIT_META" || policy.Rule != "MAJORITY Admins" {
+		t.Errorf("unexpected application admins policy: %+v", policy)
+	}
+
+	org1 := application.Organizations[0]
+	if org1.Name != "Org1MSP" || org1.MSPID != "Org1MSP" || string(org1.RootCerts[0]) != "root" || string(org1.TLSRootCerts[0]) != "tlsroot" {
+		t.Errorf("unexpected organization: %+v", org1)
+	}
+
+	if len(org1.AnchorPeers) != 1 || org1.AnchorPeers[0].Host != "peer0.org1.dummy.com" || org1.AnchorPeers[0].Port != 7051 {
+		t.Errorf("unexpected anchor peers: %+v", org1.AnchorPeers)
+	}
+
+	if policy := org1.Policies["Writers"]; policy == nil || policy.Type != "SIGNATURE" || policy.Rule != "OutOf(1, 'Org1MSP.admin', 'Org1MSP.peer')" {
+		t.Errorf("unexpected organization writers policy: %+v", policy)
+	}
+
+	ordererConfig := channelConfig.Orderer
+	if ordererConfig == nil || ordererConfig.OrdererType != "etcdraft" || ordererConfig.BatchTimeout != 2*time.Second {
+		t.Fatalf("unexpected orderer config: %+v", ordererConfig)
+	}
+
+	if ordererConfig.BatchSize.MaxMessageCount != 10 || ordererConfig.BatchSize.AbsoluteMaxBytes != 99 || ordererConfig.BatchSize.PreferredMaxBytes != 42 {
+		t.Errorf("unexpected batch size: %+v", ordererConfig.BatchSize)
+	}
+
+	if len(ordererConfig.Consenters) != 1 || ordererConfig.Consenters[0].Host != "orderer.dummy.com" || string(ordererConfig.Consenters[0].ServerTLSCert) != "server" {
+		t.Errorf("unexpected consenters: %+v", ordererConfig.Consenters)
+	}
+
+	if len(ordererConfig.Organizations) != 1 || len(ordererConfig.Organizations[0].Endpoints) != 1 {
+		t.Errorf("unexpected orderer organizations: %+v", ordererConfig.Organizations)
+	}
+
+	if _, err := extractConfigFromBlock(&common.Block{}); err == nil {
+		t.Error("should have returned an error when extracting config from an empty block")
+	}
+}
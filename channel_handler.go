@@ -1,44 +1,70 @@
 package fabclient
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"regexp"
 	"sync"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	eventclient "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 )
 
 type channelHandler interface {
-	invoke(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error)
-	query(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error)
-	queryBlock(blockNumber uint64) (*Block, error)
-	queryBlockByTxID(txID string) (*Block, error)
-	queryBlockByHash(blockHash []byte) (*Block, error)
-	queryInfo() (*BlockchainInfo, error)
-	registerChaincodeEvent(chaincodeID, eventFilter string) (<-chan *ChaincodeEvent, error)
-	unregisterChaincodeEvent(eventFilter string)
+	invoke(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error)
+	endorse(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*EndorsedTransaction, error)
+	submit(ctx context.Context, tx *EndorsedTransaction, opts ...Option) (*TransactionResponse, error)
+	submitAsync(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*Commit, error)
+	query(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error)
+	queryBlock(ctx context.Context, blockNumber uint64, opts ...Option) (*Block, error)
+	queryBlockByTxID(ctx context.Context, txID string, opts ...Option) (*Block, error)
+	queryBlockByHash(ctx context.Context, blockHash []byte, opts ...Option) (*Block, error)
+	queryChannelConfig(ctx context.Context, opts ...Option) (*ChannelConfig, error)
+	queryInfo(ctx context.Context, opts ...Option) (*BlockchainInfo, error)
+	queryTransaction(ctx context.Context, txID string, opts ...Option) (*ProcessedTransaction, error)
+	registerChaincodeEvent(chaincodeID, eventFilter string, opts ...Option) (<-chan *ChaincodeEvent, *EventRegistration, error)
+	unregisterChaincodeEvent(eventFilter string, opts ...Option)
+	registerBlockEvent(opts ...Option) (<-chan *Block, *EventRegistration, error)
+	registerFilteredBlockEvent(opts ...Option) (<-chan *FilteredBlock, *EventRegistration, error)
+	registerTxStatusEvent(txID string, opts ...Option) (<-chan *TxStatusEvent, *EventRegistration, error)
+	close()
 }
 
-type ongoingEvent struct {
-	registration fab.Registration
-	stopChan     chan chan struct{}
-	wrapChan     chan *ChaincodeEvent
+type chaincodeEventKey struct {
+	chaincodeID string
+	eventFilter string
+	subscriber  string
 }
 
 type channelHandlerClient struct {
-	client           *channel.Client
-	eventManager     *event.Client
-	underlyingLedger *ledger.Client
+	chaincodeQueryFilter fab.TargetFilter
+	channelProvider      contextAPI.ChannelProvider
+	client               *channel.Client
+	endorsingPeerFilter  fab.TargetFilter
+	eventManager         *event.Client
+	underlyingLedger     *ledger.Client
 
-	chaincodeEvents map[string]*ongoingEvent
+	chaincodeEvents map[chaincodeEventKey]*EventRegistration
+	registrations   map[*EventRegistration]*ongoingRegistration
+	closed          bool
 	mutex           sync.Mutex
 }
 
-func newChannelHandler(ctx context.ChannelProvider) (channelHandler, error) {
+func newChannelHandler(ctx contextAPI.ChannelProvider) (channelHandler, error) {
 	channelClient, err := channel.New(ctx)
 	if err != nil {
 		return nil, err
@@ -54,12 +80,21 @@ func newChannelHandler(ctx context.ChannelProvider) (channelHandler, error) {
 		return nil, err
 	}
 
+	channelContext, err := ctx()
+	if err != nil {
+		return nil, err
+	}
+
 	client := &channelHandlerClient{
-		client:           channelClient,
-		eventManager:     eventManager,
-		underlyingLedger: ledgerClient,
-		chaincodeEvents:  make(map[string]*ongoingEvent),
-		mutex:            sync.Mutex{},
+		chaincodeQueryFilter: filter.NewEndpointFilter(channelContext, filter.ChaincodeQuery),
+		channelProvider:      ctx,
+		client:               channelClient,
+		endorsingPeerFilter:  filter.NewEndpointFilter(channelContext, filter.EndorsingPeer),
+		eventManager:         eventManager,
+		underlyingLedger:     ledgerClient,
+		chaincodeEvents:      make(map[chaincodeEventKey]*EventRegistration),
+		registrations:        make(map[*EventRegistration]*ongoingRegistration),
+		mutex:                sync.Mutex{},
 	}
 
 	return client, nil
@@ -67,87 +102,602 @@ func newChannelHandler(ctx context.ChannelProvider) (channelHandler, error) {
 
 var _ channelHandler = (*channelHandlerClient)(nil)
 
-func (chn *channelHandlerClient) invoke(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
-	response, err := chn.client.Execute(convertChaincodeRequest(request), convertOptions(opts...)...)
-	return convertChaincodeTransactionResponse(response), err
+// invoke executes the transaction as channel.Client.Execute does.
+func (chn *channelHandlerClient) invoke(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
+	return chn.execute(ctx, convertChaincodeRequest(request), newExecuteHandler, opts...)
+}
+
+// endorse collects the endorsements of the transaction without submitting it.
+func (chn *channelHandlerClient) endorse(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*EndorsedTransaction, error) {
+	response, err := chn.client.InvokeHandler(newExecuteHandler(nil), convertChaincodeRequest(request), convertOptions(ctx, chn.endorsingPeerFilter, opts...)...)
+	if err != nil {
+		return nil, convertChaincodeError(string(response.TransactionID), err)
+	}
+
+	channelContext, err := chn.channelProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return convertEndorsedTransaction(channelContext, response)
+}
+
+// submit sends a transaction endorsed beforehand to the orderer and waits for it to be committed. The transaction must
+// be submitted by the identity which created the proposal, the envelope sent to the orderer being signed by the latter.
+func (chn *channelHandlerClient) submit(ctx context.Context, tx *EndorsedTransaction, opts ...Option) (*TransactionResponse, error) {
+	channelContext, err := chn.channelProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	request, proposal, responses, err := convertEndorsements(channelContext, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	newHandler := func(next invoke.Handler) invoke.Handler {
+		return newEndorsedHandler(proposal, responses, next)
+	}
+
+	// a retry would broadcast the same transaction again, it would then be rejected as a duplicate
+	opts = append(opts, optionFunc(func(o *options) {
+		o.retryPolicy = nil
+	}))
+
+	return chn.execute(ctx, request, newHandler, opts...)
+}
+
+// execute runs the handler returned by newHandler, the commit handler it is given records the number of the block the
+// transaction has been committed in. Since the execution timeout bounds the whole request, the commit timeout is added
+// to it.
+func (chn *channelHandlerClient) execute(ctx context.Context, request channel.Request, newHandler func(next invoke.Handler) invoke.Handler, opts ...Option) (*TransactionResponse, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	commit := &commitHandler{timeout: o.commitTimeout}
+	requestOpts := convertOptions(ctx, chn.endorsingPeerFilter, opts...)
+
+	if o.commitTimeout > 0 {
+		executeTimeout := o.endorsementTimeout
+		if executeTimeout <= 0 {
+			timeout, err := chn.defaultTimeout(fab.Execute)
+			if err != nil {
+				return nil, err
+			}
+
+			executeTimeout = timeout
+		}
+
+		requestOpts = append(requestOpts, channel.WithTimeout(fab.Execute, executeTimeout+o.commitTimeout))
+	}
+
+	response, err := chn.client.InvokeHandler(newHandler(commit), request, requestOpts...)
+	if err != nil {
+		return convertChaincodeTransactionResponse(response, 0), convertChaincodeError(string(response.TransactionID), err)
+	}
+
+	return convertChaincodeTransactionResponse(response, commit.blockNumber), nil
+}
+
+// submitAsync endorses the transaction and sends it to the orderer as invoke does, but returns without waiting for it to
+// be committed. The status registration is tracked as the other event registrations so that closing the handler
+// resolves the commits still pending. The commit is awaited for the commit timeout, the default execution timeout
+// otherwise.
+func (chn *channelHandlerClient) submitAsync(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*Commit, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	commitTimeout := o.commitTimeout
+	if commitTimeout <= 0 {
+		timeout, err := chn.defaultTimeout(fab.Execute)
+		if err != nil {
+			return nil, err
+		}
+
+		commitTimeout = timeout
+	}
+
+	submit := &submitHandler{}
+
+	submitted := false
+	defer func() {
+		if !submitted {
+			submit.release()
+		}
+	}()
+
+	response, err := chn.client.InvokeHandler(newExecuteHandler(submit), convertChaincodeRequest(request), convertOptions(ctx, chn.endorsingPeerFilter, opts...)...)
+	if err != nil {
+		return nil, convertChaincodeError(string(response.TransactionID), err)
+	}
+
+	submitted = true
+
+	statuses := make(chan *TxStatusEvent, 1)
+	ongoing := chn.trackRegistration(submit.eventService, submit.registration, func() {}, newEventSink(statuses), OverflowDropNewest)
+
+	go ongoing.forward(
+		func() (interface{}, bool) {
+			select {
+			case event, ok := <-submit.statusNotifier:
+				return event, ok
+			case <-ongoing.stop:
+				return nil, false
+			}
+		},
+		func(event interface{}) bool {
+			ongoing.deliver(convertTxStatusEvent(event.(*fab.TxStatusEvent)))
+			// a transaction is committed once
+			return false
+		},
+	)
+
+	commit := newCommit(string(response.TransactionID))
+	go commit.resolve(statuses, ongoing.eventRegistration, commitTimeout)
+
+	return commit, nil
 }
 
-func (chn *channelHandlerClient) query(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
-	response, err := chn.client.Query(convertChaincodeRequest(request), convertOptions(opts...)...)
-	return convertChaincodeTransactionResponse(response), err
+func (chn *channelHandlerClient) query(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
+	response, err := chn.client.Query(convertChaincodeRequest(request), convertOptions(ctx, chn.chaincodeQueryFilter, opts...)...)
+	return convertChaincodeTransactionResponse(response, 0), convertChaincodeError(string(response.TransactionID), err)
 }
 
-func (chn *channelHandlerClient) queryBlock(blockNumber uint64) (*Block, error) {
-	block, err := chn.underlyingLedger.QueryBlock(blockNumber)
+func (chn *channelHandlerClient) queryBlock(ctx context.Context, blockNumber uint64, opts ...Option) (*Block, error) {
+	block, err := chn.underlyingLedger.QueryBlock(blockNumber, convertLedgerOptions(ctx, opts...)...)
 	return convertBlock(block), err
 }
-func (chn *channelHandlerClient) queryBlockByHash(blockHash []byte) (*Block, error) {
-	block, err := chn.underlyingLedger.QueryBlockByHash(blockHash)
+func (chn *channelHandlerClient) queryBlockByHash(ctx context.Context, blockHash []byte, opts ...Option) (*Block, error) {
+	block, err := chn.underlyingLedger.QueryBlockByHash(blockHash, convertLedgerOptions(ctx, opts...)...)
 	return convertBlock(block), err
 }
 
-func (chn *channelHandlerClient) queryBlockByTxID(txID string) (*Block, error) {
-	block, err := chn.underlyingLedger.QueryBlockByTxID(fab.TransactionID(txID))
+func (chn *channelHandlerClient) queryBlockByTxID(ctx context.Context, txID string, opts ...Option) (*Block, error) {
+	block, err := chn.underlyingLedger.QueryBlockByTxID(fab.TransactionID(txID), convertLedgerOptions(ctx, opts...)...)
 	return convertBlock(block), err
 }
 
-func (chn *channelHandlerClient) queryInfo() (*BlockchainInfo, error) {
-	blockchainInfo, err := chn.underlyingLedger.QueryInfo()
+func (chn *channelHandlerClient) queryChannelConfig(ctx context.Context, opts ...Option) (*ChannelConfig, error) {
+	channelContext, err := chn.channelProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := chn.underlyingLedger.QueryConfigBlock(convertLedgerOptions(ctx, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := extractConfigFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeChannelConfig(channelContext.ChannelID(), block.GetHeader().GetNumber(), config)
+}
+
+func (chn *channelHandlerClient) queryInfo(ctx context.Context, opts ...Option) (*BlockchainInfo, error) {
+	blockchainInfo, err := chn.underlyingLedger.QueryInfo(convertLedgerOptions(ctx, opts...)...)
 	return convertBlockchainInfo(blockchainInfo), err
 }
 
-func (chn *channelHandlerClient) registerChaincodeEvent(chaincodeID, eventFilter string) (<-chan *ChaincodeEvent, error) {
+// queryTransaction also queries the block which contains the transaction since the processed transaction returned by the
+// ledger does not hold the block number.
+func (chn *channelHandlerClient) queryTransaction(ctx context.Context, txID string, opts ...Option) (*ProcessedTransaction, error) {
+	ledgerOpts := convertLedgerOptions(ctx, opts...)
+
+	processedTransaction, err := chn.underlyingLedger.QueryTransaction(fab.TransactionID(txID), ledgerOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := chn.underlyingLedger.QueryBlockByTxID(fab.TransactionID(txID), ledgerOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := decodeTransaction(processedTransaction.TransactionEnvelope, block.GetHeader().GetNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	tx.ValidationCode = TxValidationCode(processedTransaction.ValidationCode)
+
+	return &ProcessedTransaction{
+		Transaction:    tx,
+		ValidationCode: tx.ValidationCode,
+		BlockNumber:    block.GetHeader().GetNumber(),
+	}, nil
+}
+
+// registerChaincodeEvent extracts the chaincode events from the filtered blocks instead of relying on the chaincode event
+// registrations of the SDK, which accept a single registration per chaincode and event filter. Each subscriber thus gets
+// its own registration and the index of the transaction which emitted the events is known for checkpointing.
+// When a checkpointer is given, the checkpoint is saved once the events of a transaction have been delivered, as well as
+// at the end of each block. A failed save only results in events being delivered again after a restart. Events discarded
+// by the overflow policy are not delivered again after a restart.
+func (chn *channelHandlerClient) registerChaincodeEvent(chaincodeID, eventFilter string, opts ...Option) (<-chan *ChaincodeEvent, *EventRegistration, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	key := chaincodeEventKey{chaincodeID: chaincodeID, eventFilter: eventFilter, subscriber: o.subscriber}
+
+	// the key is reserved while the registration is set up so that the lock is not held while loading the checkpoint and
+	// connecting to the peers
 	chn.mutex.Lock()
-	defer chn.mutex.Unlock()
+	if _, ok := chn.chaincodeEvents[key]; ok {
+		chn.mutex.Unlock()
+		return nil, nil, fmt.Errorf("event filter '%s' of chaincode '%s' already registered by subscriber '%s'", eventFilter, chaincodeID, o.subscriber)
+	}
+
+	chn.chaincodeEvents[key] = nil
+	chn.mutex.Unlock()
+
+	registered := false
+	defer func() {
+		if !registered {
+			chn.mutex.Lock()
+			delete(chn.chaincodeEvents, key)
+			chn.mutex.Unlock()
+		}
+	}()
+
+	eventRegExp, err := regexp.Compile(eventFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': %w", eventFilter, chaincodeID, err)
+	}
+
+	var (
+		channelID  string
+		checkpoint *Checkpoint
+	)
+
+	if o.checkpointer != nil {
+		channelContext, err := chn.channelProvider()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': %w", eventFilter, chaincodeID, err)
+		}
+
+		channelID = channelContext.ChannelID()
+		checkpoint, err = o.checkpointer.Load(channelID, o.subscriptionID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load checkpoint for subscription '%s' on channel '%s': %w", o.subscriptionID, channelID, err)
+		}
 
-	if _, ok := chn.chaincodeEvents[eventFilter]; ok {
-		return nil, fmt.Errorf("event filter '%s' already registered", eventFilter)
+		if checkpoint != nil {
+			opts = append(opts, WithStartBlock(checkpoint.BlockNumber))
+		}
 	}
 
-	registration, ch, err := chn.eventManager.RegisterChaincodeEvent(chaincodeID, eventFilter)
+	eventService, release, err := chn.selectEventService(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': %w", eventFilter, chaincodeID, err)
+		return nil, nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': %w", eventFilter, chaincodeID, err)
 	}
 
-	stopChan := make(chan chan struct{})
-	wrapChan := make(chan *ChaincodeEvent)
-	chn.chaincodeEvents[eventFilter] = &ongoingEvent{
-		registration: registration,
-		stopChan:     stopChan,
-		wrapChan:     wrapChan,
+	registration, ch, err := eventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': %w", eventFilter, chaincodeID, err)
+	}
+
+	wrapChan := make(chan *ChaincodeEvent, o.eventBufferSize)
+	ongoing := chn.newOngoingRegistration(eventService, registration, release, newEventSink(wrapChan), o.overflowPolicy)
+	ongoing.chaincodeEvent = &key
+
+	chn.mutex.Lock()
+	if chn.closed {
+		chn.mutex.Unlock()
+		eventService.Unregister(registration)
+		release()
+		return nil, nil, fmt.Errorf("failed to register chaincode event '%s' for chaincode '%s': handler is closed", eventFilter, chaincodeID)
+	}
+
+	chn.registrations[ongoing.eventRegistration] = ongoing
+	chn.chaincodeEvents[key] = ongoing.eventRegistration
+	registered = true
+	chn.mutex.Unlock()
+
+	processed := func(blockNumber uint64, txIndex int) bool {
+		if checkpoint == nil {
+			return false
+		}
+
+		return blockNumber < checkpoint.BlockNumber || (blockNumber == checkpoint.BlockNumber && txIndex <= checkpoint.TxIndex)
 	}
 
-	go func() {
-		for {
+	go ongoing.forward(
+		func() (interface{}, bool) {
 			select {
-			case event := <-ch:
-				wrapChan <- convertChaincodeEvent(event)
-			case witness := <-stopChan:
-				witness <- struct{}{}
-				return
+			case event, ok := <-ch:
+				return event, ok
+			case <-ongoing.stop:
+				return nil, false
 			}
+		},
+		func(event interface{}) bool {
+			block := convertFilteredBlock(event.(*fab.FilteredBlockEvent))
+			lastTxIndex := len(block.FilteredTransactions) - 1
+			for txIndex, tx := range block.FilteredTransactions {
+				if processed(block.Number, txIndex) {
+					continue
+				}
+
+				delivered := false
+				if tx.TxValidationCode == TxValidationCodeValid {
+					for _, ccEvent := range tx.ChaincodeEvents {
+						if ccEvent.ChaincodeID != chaincodeID || !eventRegExp.MatchString(ccEvent.EventName) {
+							continue
+						}
+
+						if !ongoing.deliver(ccEvent) {
+							return false
+						}
+
+						delivered = true
+					}
+				}
+
+				if o.checkpointer != nil && (delivered || txIndex == lastTxIndex) {
+					checkpoint = &Checkpoint{BlockNumber: block.Number, TxIndex: txIndex}
+					o.checkpointer.Save(channelID, o.subscriptionID, *checkpoint)
+				}
+			}
+
+			return true
+		},
+	)
+
+	return wrapChan, ongoing.eventRegistration, nil
+}
+
+func (chn *channelHandlerClient) unregisterChaincodeEvent(eventFilter string, opts ...Option) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	var eventRegistrations []*EventRegistration
+
+	chn.mutex.Lock()
+	for key, eventRegistration := range chn.chaincodeEvents {
+		// registrations still being set up are reserved with a nil registration
+		if eventRegistration != nil && key.eventFilter == eventFilter && key.subscriber == o.subscriber {
+			eventRegistrations = append(eventRegistrations, eventRegistration)
 		}
-	}()
+	}
+	chn.mutex.Unlock()
+
+	for _, eventRegistration := range eventRegistrations {
+		eventRegistration.Unregister()
+	}
+}
+
+func (chn *channelHandlerClient) registerBlockEvent(opts ...Option) (<-chan *Block, *EventRegistration, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	eventService, release, err := chn.selectEventService(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register block event: %w", err)
+	}
+
+	registration, ch, err := eventService.RegisterBlockEvent()
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to register block event: %w", err)
+	}
+
+	wrapChan := make(chan *Block, o.eventBufferSize)
+	ongoing := chn.trackRegistration(eventService, registration, release, newEventSink(wrapChan), o.overflowPolicy)
+
+	go ongoing.forward(
+		func() (interface{}, bool) {
+			select {
+			case event, ok := <-ch:
+				return event, ok
+			case <-ongoing.stop:
+				return nil, false
+			}
+		},
+		func(event interface{}) bool {
+			return ongoing.deliver(convertBlock(event.(*fab.BlockEvent).Block))
+		},
+	)
+
+	return wrapChan, ongoing.eventRegistration, nil
+}
+
+func (chn *channelHandlerClient) registerFilteredBlockEvent(opts ...Option) (<-chan *FilteredBlock, *EventRegistration, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	eventService, release, err := chn.selectEventService(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register filtered block event: %w", err)
+	}
+
+	registration, ch, err := eventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to register filtered block event: %w", err)
+	}
+
+	wrapChan := make(chan *FilteredBlock, o.eventBufferSize)
+	ongoing := chn.trackRegistration(eventService, registration, release, newEventSink(wrapChan), o.overflowPolicy)
+
+	go ongoing.forward(
+		func() (interface{}, bool) {
+			select {
+			case event, ok := <-ch:
+				return event, ok
+			case <-ongoing.stop:
+				return nil, false
+			}
+		},
+		func(event interface{}) bool {
+			return ongoing.deliver(convertFilteredBlock(event.(*fab.FilteredBlockEvent)))
+		},
+	)
+
+	return wrapChan, ongoing.eventRegistration, nil
+}
+
+func (chn *channelHandlerClient) registerTxStatusEvent(txID string, opts ...Option) (<-chan *TxStatusEvent, *EventRegistration, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	eventService, release, err := chn.selectEventService(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register transaction status event for transaction '%s': %w", txID, err)
+	}
+
+	registration, ch, err := eventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to register transaction status event for transaction '%s': %w", txID, err)
+	}
+
+	wrapChan := make(chan *TxStatusEvent, o.eventBufferSize)
+	ongoing := chn.trackRegistration(eventService, registration, release, newEventSink(wrapChan), o.overflowPolicy)
+
+	go ongoing.forward(
+		func() (interface{}, bool) {
+			select {
+			case event, ok := <-ch:
+				return event, ok
+			case <-ongoing.stop:
+				return nil, false
+			}
+		},
+		func(event interface{}) bool {
+			return ongoing.deliver(convertTxStatusEvent(event.(*fab.TxStatusEvent)))
+		},
+	)
+
+	return wrapChan, ongoing.eventRegistration, nil
+}
+
+// close unregisters all the ongoing event registrations of the handler, closing their event channels. The chaincode
+// event registrations still being set up are rejected when they complete.
+func (chn *channelHandlerClient) close() {
+	chn.mutex.Lock()
+	chn.closed = true
+	eventRegistrations := make([]*EventRegistration, 0, len(chn.registrations))
+	for eventRegistration := range chn.registrations {
+		eventRegistrations = append(eventRegistrations, eventRegistration)
+	}
+	chn.mutex.Unlock()
+
+	for _, eventRegistration := range eventRegistrations {
+		eventRegistration.Unregister()
+	}
+}
+
+func (chn *channelHandlerClient) newOngoingRegistration(eventService fab.EventService, registration fab.Registration, release func(), sink eventSink, policy OverflowPolicy) *ongoingRegistration {
+	ongoing := &ongoingRegistration{
+		eventService: eventService,
+		policy:       policy,
+		registration: registration,
+		release:      release,
+		sink:         sink,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	var eventRegistration *EventRegistration
+	eventRegistration = newEventRegistration(func() {
+		chn.unregisterEvent(eventRegistration)
+	})
+	ongoing.eventRegistration = eventRegistration
 
-	return wrapChan, nil
+	return ongoing
 }
 
-func (chn *channelHandlerClient) unregisterChaincodeEvent(eventFilter string) {
+func (chn *channelHandlerClient) trackRegistration(eventService fab.EventService, registration fab.Registration, release func(), sink eventSink, policy OverflowPolicy) *ongoingRegistration {
+	ongoing := chn.newOngoingRegistration(eventService, registration, release, sink, policy)
+
 	chn.mutex.Lock()
-	defer chn.mutex.Unlock()
+	chn.registrations[ongoing.eventRegistration] = ongoing
+	chn.mutex.Unlock()
+
+	return ongoing
+}
+
+// unregisterEvent stops the forwarding goroutine before releasing the underlying registration, a consumer which does not
+// read its event channel anymore therefore cannot prevent the registration from being removed.
+func (chn *channelHandlerClient) unregisterEvent(eventRegistration *EventRegistration) {
+	chn.mutex.Lock()
+	ongoing, ok := chn.registrations[eventRegistration]
+	delete(chn.registrations, eventRegistration)
+	if ok && ongoing.chaincodeEvent != nil && chn.chaincodeEvents[*ongoing.chaincodeEvent] == eventRegistration {
+		delete(chn.chaincodeEvents, *ongoing.chaincodeEvent)
+	}
+	chn.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	close(ongoing.stop)
+	<-ongoing.done
+	ongoing.eventService.Unregister(ongoing.registration)
+	ongoing.release()
+}
+
+// selectEventService returns the event service to use for a registration. Registrations replaying events from a given
+// position need their own event service since the ones cached by the SDK ignore the seek options once connected.
+// The returned function releases the event service once the registration is over.
+func (chn *channelHandlerClient) selectEventService(opts ...Option) (fab.EventService, func(), error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	if len(o.seekType) == 0 {
+		return chn.eventManager, func() {}, nil
+	}
+
+	channelContext, err := chn.channelProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	channelConfig, err := channelContext.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if _, ok := chn.chaincodeEvents[eventFilter]; ok {
-		witness := make(chan struct{})
-		ongoingEvent := chn.chaincodeEvents[eventFilter]
-		ongoingEvent.stopChan <- witness
-		<-witness
-		chn.eventManager.Unregister(ongoingEvent.registration)
-		close(ongoingEvent.stopChan)
-		close(ongoingEvent.wrapChan)
-		delete(chn.chaincodeEvents, eventFilter)
+	discovery, err := channelContext.ChannelService().Discovery()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var eventService *deliverclient.Client
+	if o.seekType == seek.FromBlock {
+		eventService, err = deliverclient.New(channelContext, channelConfig, discovery,
+			eventclient.WithBlockEvents(), deliverclient.WithSeekType(o.seekType), deliverclient.WithBlockNum(o.startBlock))
+	} else {
+		eventService, err = deliverclient.New(channelContext, channelConfig, discovery,
+			eventclient.WithBlockEvents(), deliverclient.WithSeekType(o.seekType))
+	}
+
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return
+	return eventService, eventService.Close, nil
 }
 
 func convertBlock(b *common.Block) *Block {
@@ -190,9 +740,56 @@ func convertBlockchainInfo(info *fab.BlockchainInfoResponse) *BlockchainInfo {
 	}
 }
 
-func convertChaincodeEvent(e *fab.CCEvent) *ChaincodeEvent {
-	event := ChaincodeEvent(*e)
-	return &event
+func convertFilteredBlock(e *fab.FilteredBlockEvent) *FilteredBlock {
+	if e == nil || e.FilteredBlock == nil {
+		return nil
+	}
+
+	transactions := make([]*FilteredTransaction, 0, len(e.FilteredBlock.FilteredTransactions))
+	for _, tx := range e.FilteredBlock.FilteredTransactions {
+		var events []*ChaincodeEvent
+		for _, action := range tx.GetTransactionActions().GetChaincodeActions() {
+			ccEvent := action.GetChaincodeEvent()
+			if ccEvent == nil {
+				continue
+			}
+
+			events = append(events, &ChaincodeEvent{
+				TxID:        ccEvent.TxId,
+				ChaincodeID: ccEvent.ChaincodeId,
+				EventName:   ccEvent.EventName,
+				Payload:     ccEvent.Payload,
+				BlockNumber: e.FilteredBlock.Number,
+				SourceURL:   e.SourceURL,
+			})
+		}
+
+		transactions = append(transactions, &FilteredTransaction{
+			TxID:             tx.Txid,
+			Type:             tx.Type.String(),
+			TxValidationCode: TxValidationCode(tx.TxValidationCode),
+			ChaincodeEvents:  events,
+		})
+	}
+
+	return &FilteredBlock{
+		ChannelID:            e.FilteredBlock.ChannelId,
+		Number:               e.FilteredBlock.Number,
+		FilteredTransactions: transactions,
+	}
+}
+
+func convertTxStatusEvent(e *fab.TxStatusEvent) *TxStatusEvent {
+	if e == nil {
+		return nil
+	}
+
+	return &TxStatusEvent{
+		TxID:             e.TxID,
+		TxValidationCode: TxValidationCode(e.TxValidationCode),
+		BlockNumber:      e.BlockNumber,
+		SourceURL:        e.SourceURL,
+	}
 }
 
 func convertChaincodeRequest(request *ChaincodeRequest) channel.Request {
@@ -218,16 +815,177 @@ func convertChaincodeRequest(request *ChaincodeRequest) channel.Request {
 	}
 }
 
-func convertChaincodeTransactionResponse(response channel.Response) *TransactionResponse {
-	return &TransactionResponse{
-		Payload:       response.Payload,
-		Status:        response.ChaincodeStatus,
+func convertChaincodeTransactionResponse(response channel.Response, blockNumber uint64) *TransactionResponse {
+	transactionResponse := &TransactionResponse{
+		Payload:           response.Payload,
+		Status:            response.ChaincodeStatus,
+		TransactionID:     string(response.TransactionID),
+		TxValidationCode:  TxValidationCode(response.TxValidationCode),
+		BlockNumber:       blockNumber,
+		ProposalResponses: make([]*ProposalResponse, 0, len(response.Responses)),
+	}
+
+	for _, r := range response.Responses {
+		transactionResponse.ProposalResponses = append(transactionResponse.ProposalResponses, &ProposalResponse{
+			Endorser:        r.Endorser,
+			Status:          r.Status,
+			ChaincodeStatus: r.ChaincodeStatus,
+			Message:         r.GetResponse().GetMessage(),
+			Payload:         r.GetResponse().GetPayload(),
+		})
+	}
+
+	if len(response.Responses) == 0 {
+		return transactionResponse
+	}
+
+	// the endorsements have been validated by the SDK, the simulation results are the same for all the endorsing peers
+	chaincodeAction, err := decodeChaincodeAction(response.Responses[0].GetPayload())
+	if err != nil {
+		return transactionResponse
+	}
+
+	if len(chaincodeAction.Results) > 0 {
+		if readWriteSets, err := decodeReadWriteSets(chaincodeAction.Results); err == nil {
+			transactionResponse.ReadWriteSets = readWriteSets
+		}
+	}
+
+	if event, err := decodeChaincodeEvent(chaincodeAction.Events); err == nil && event != nil {
+		event.TxID = transactionResponse.TransactionID
+		event.BlockNumber = blockNumber
+		transactionResponse.Event = event
+	}
+
+	return transactionResponse
+}
+
+// defaultTimeout returns the timeout of the given type defined in the connection profile.
+func (chn *channelHandlerClient) defaultTimeout(timeoutType fab.TimeoutType) (time.Duration, error) {
+	channelContext, err := chn.channelProvider()
+	if err != nil {
+		return 0, err
+	}
+
+	return channelContext.EndpointConfig().Timeout(timeoutType), nil
+}
+
+// convertEndorsedTransaction marshals the proposal the SDK sent to the endorsing peers along with the proposal responses.
+func convertEndorsedTransaction(channelContext contextAPI.Channel, response channel.Response) (*EndorsedTransaction, error) {
+	proposal, err := proto.Marshal(response.Proposal.Proposal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal proposal: %w", err)
+	}
+
+	endorsements := make([]Endorsement, 0, len(response.Responses))
+	for _, proposalResponse := range response.Responses {
+		endorsement, err := proto.Marshal(proposalResponse.ProposalResponse)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal proposal response of '%s': %w", proposalResponse.Endorser, err)
+		}
+
+		endorsements = append(endorsements, Endorsement{
+			Endorser:         proposalResponse.Endorser,
+			ProposalResponse: endorsement,
+		})
+	}
+
+	return &EndorsedTransaction{
 		TransactionID: string(response.TransactionID),
+		ChannelID:     channelContext.ChannelID(),
+		Response:      convertChaincodeTransactionResponse(response, 0),
+		Proposal:      proposal,
+		Endorsements:  endorsements,
+	}, nil
+}
+
+// convertEndorsements unmarshals the proposal and the proposal responses of an endorsed transaction. It checks that the
+// proposal targets the channel and has been created by the identity of the channel context, and that each proposal
+// response has been endorsed by a distinct identity. The endorsements themselves are validated by the handler submitting
+// the transaction.
+func convertEndorsements(channelContext contextAPI.Channel, tx *EndorsedTransaction) (channel.Request, *fab.TransactionProposal, []*fab.TransactionProposalResponse, error) {
+	if len(tx.Endorsements) == 0 {
+		return channel.Request{}, nil, nil, fmt.Errorf("transaction '%s' has not been endorsed", tx.TransactionID)
+	}
+
+	proposal := &peer.Proposal{}
+	if err := proto.Unmarshal(tx.Proposal, proposal); err != nil {
+		return channel.Request{}, nil, nil, fmt.Errorf("failed to unmarshal proposal: %w", err)
+	}
+
+	channelHeader, signatureHeader, chaincodeSpec, err := decodeProposal(proposal)
+	if err != nil {
+		return channel.Request{}, nil, nil, err
+	}
+
+	if channelHeader.ChannelId != channelContext.ChannelID() {
+		return channel.Request{}, nil, nil, fmt.Errorf("transaction '%s' has been endorsed on channel '%s'", channelHeader.TxId, channelHeader.ChannelId)
+	}
+
+	creator, err := channelContext.Serialize()
+	if err != nil {
+		return channel.Request{}, nil, nil, fmt.Errorf("failed to serialize identity: %w", err)
+	}
+
+	if !bytes.Equal(creator, signatureHeader.Creator) {
+		return channel.Request{}, nil, nil, fmt.Errorf("transaction '%s' has been endorsed for another identity", channelHeader.TxId)
+	}
+
+	endorsers := make(map[string]string, len(tx.Endorsements))
+	responses := make([]*fab.TransactionProposalResponse, 0, len(tx.Endorsements))
+	for _, endorsement := range tx.Endorsements {
+		proposalResponse := &peer.ProposalResponse{}
+		if err := proto.Unmarshal(endorsement.ProposalResponse, proposalResponse); err != nil {
+			return channel.Request{}, nil, nil, fmt.Errorf("failed to unmarshal proposal response of '%s': %w", endorsement.Endorser, err)
+		}
+
+		identity := string(proposalResponse.GetEndorsement().GetEndorser())
+		if len(identity) == 0 {
+			return channel.Request{}, nil, nil, fmt.Errorf("proposal response of '%s' has not been endorsed", endorsement.Endorser)
+		}
+
+		if endorser, ok := endorsers[identity]; ok {
+			return channel.Request{}, nil, nil, fmt.Errorf("proposal responses of '%s' and '%s' have been endorsed by the same identity", endorser, endorsement.Endorser)
+		}
+
+		endorsers[identity] = endorsement.Endorser
+
+		response := &fab.TransactionProposalResponse{
+			Endorser:         endorsement.Endorser,
+			ProposalResponse: proposalResponse,
+			Status:           proposalResponse.GetResponse().GetStatus(),
+			ChaincodeStatus:  proposalResponse.GetResponse().GetStatus(),
+		}
+
+		if chaincodeAction, err := decodeChaincodeAction(proposalResponse.Payload); err == nil && chaincodeAction.Response != nil {
+			response.ChaincodeStatus = chaincodeAction.Response.Status
+		}
+
+		responses = append(responses, response)
+	}
+
+	request := channel.Request{
+		ChaincodeID: chaincodeSpec.GetChaincodeId().GetName(),
+	}
+
+	if args := chaincodeSpec.GetInput().GetArgs(); len(args) > 0 {
+		request.Fcn = string(args[0])
+		request.Args = args[1:]
+	}
+
+	txProposal := &fab.TransactionProposal{
+		TxnID:    fab.TransactionID(channelHeader.TxId),
+		Proposal: proposal,
 	}
+
+	return request, txProposal, responses, nil
 }
 
-func convertOptions(opts ...Option) []channel.RequestOption {
-	convertedOpts := make([]channel.RequestOption, 0, len(opts))
+// convertOptions converts the options of a chaincode request. Unless target peers are given, the request is sent to
+// the peers selected by the SDK which are accepted by the role filter as well as the target organizations and filter.
+func convertOptions(ctx context.Context, roleFilter fab.TargetFilter, opts ...Option) []channel.RequestOption {
+	convertedOpts := make([]channel.RequestOption, 0, len(opts)+6)
+	convertedOpts = append(convertedOpts, channel.WithParentContext(ctx))
 
 	o := &options{
 		ordererResponseTimeout: -1,
@@ -241,5 +999,96 @@ func convertOptions(opts ...Option) []channel.RequestOption {
 		convertedOpts = append(convertedOpts, channel.WithTimeout(fab.OrdererResponse, o.ordererResponseTimeout))
 	}
 
+	if o.endorsementTimeout > 0 {
+		convertedOpts = append(convertedOpts, channel.WithTimeout(fab.Execute, o.endorsementTimeout))
+	}
+
+	if o.queryTimeout > 0 {
+		convertedOpts = append(convertedOpts, channel.WithTimeout(fab.Query, o.queryTimeout))
+	}
+
+	if o.retryPolicy != nil {
+		convertedOpts = append(convertedOpts, channel.WithRetry(o.retryPolicy.retryOpts(retry.ChannelClientRetryableCodes)))
+
+		if beforeRetry := o.retryPolicy.beforeRetry(ctx); beforeRetry != nil {
+			convertedOpts = append(convertedOpts, channel.WithBeforeRetry(func(err error) {
+				// the retry handler of the SDK stops retrying on its own once the request context is done
+				_ = beforeRetry(err)
+			}))
+		}
+	}
+
+	if len(o.targetPeers) > 0 {
+		convertedOpts = append(convertedOpts, channel.WithTargetEndpoints(o.targetPeers...))
+	} else if targetFilter := newTargetFilter(roleFilter, o); targetFilter != nil {
+		convertedOpts = append(convertedOpts, channel.WithTargetFilter(targetFilter))
+	}
+
+	return convertedOpts
+}
+
+// convertLedgerOptions converts the options of a ledger query, the peers selected by the SDK already being filtered
+// according to their role.
+func convertLedgerOptions(ctx context.Context, opts ...Option) []ledger.RequestOption {
+	convertedOpts := []ledger.RequestOption{ledger.WithParentContext(ctx)}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+
+	if len(o.targetPeers) > 0 {
+		convertedOpts = append(convertedOpts, ledger.WithTargetEndpoints(o.targetPeers...))
+	} else if targetFilter := newTargetFilter(nil, o); targetFilter != nil {
+		convertedOpts = append(convertedOpts, ledger.WithTargetFilter(targetFilter))
+	}
+
 	return convertedOpts
 }
+
+// targetFilter accepts the peers accepted by the role filter which belong to one of the target organizations and are
+// accepted by the filter given as request options.
+type targetFilter struct {
+	roleFilter fab.TargetFilter
+	mspIDs     []string
+	accept     func(Peer) bool
+}
+
+func newTargetFilter(roleFilter fab.TargetFilter, o *options) fab.TargetFilter {
+	if len(o.targetOrgs) == 0 && o.targetFilter == nil {
+		return roleFilter
+	}
+
+	return &targetFilter{
+		roleFilter: roleFilter,
+		mspIDs:     o.targetOrgs,
+		accept:     o.targetFilter,
+	}
+}
+
+func (f *targetFilter) Accept(peer fab.Peer) bool {
+	if f.roleFilter != nil && !f.roleFilter.Accept(peer) {
+		return false
+	}
+
+	if len(f.mspIDs) > 0 && !containsString(f.mspIDs, peer.MSPID()) {
+		return false
+	}
+
+	return f.accept == nil || f.accept(convertPeer(peer))
+}
+
+func convertPeer(peer fab.Peer) Peer {
+	converted := Peer{
+		URL:   peer.URL(),
+		MSPID: peer.MSPID(),
+	}
+
+	if properties := peer.Properties(); properties != nil {
+		if ledgerHeight, ok := properties[fab.PropertyLedgerHeight].(uint64); ok {
+			converted.LedgerHeight = ledgerHeight
+		}
+	}
+
+	return converted
+}
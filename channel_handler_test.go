@@ -1,9 +1,21 @@
 package fabclient
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 )
 
 var (
@@ -35,7 +47,29 @@ func writeToLedger(t *testing.T, client *Client) {
 		t.Fatal(err)
 	}
 
+	if res.TxValidationCode != TxValidationCodeValid || res.BlockNumber == 0 || len(res.ProposalResponses) == 0 || len(res.ReadWriteSets) == 0 {
+		t.Errorf("transaction response should hold the endorsements and commit details: %+v", res)
+	}
+
 	txID = res.TransactionID
+
+	retryPolicy := RetryPolicy{
+		Attempts:       3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		BackoffFactor:  2,
+		Jitter:         50 * time.Millisecond,
+		RetryOn:        RetryUnavailable | RetryReadConflicts,
+	}
+
+	res, err = client.Invoke(req, WithEndorsementTimeout(20*time.Second), WithCommitTimeout(20*time.Second), WithRetryPolicy(retryPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.TxValidationCode != TxValidationCodeValid || res.BlockNumber == 0 {
+		t.Errorf("transaction should have been committed with the timeouts and the retry policy: %+v", res)
+	}
 }
 
 func readFromLedger(t *testing.T, client *Client) {
@@ -66,18 +100,112 @@ func readFromLedger(t *testing.T, client *Client) {
 	if result.Content != "this is a content test" {
 		t.Error(`content should be: "this is a content test"`)
 	}
+
+	if _, err := client.Query(req, WithQueryTimeout(10*time.Second), WithRetryPolicy(RetryPolicy{Attempts: 2, RetryOn: RetryTimeouts})); err != nil {
+		t.Error(err)
+	}
+
+	res, err = client.Query(req, WithTargetOrgs("Org1MSP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.ProposalResponses) == 0 || res.ProposalResponses[0].Endorser != "peer0.org1.dummy.com:7051" {
+		t.Errorf("query should have been sent to the peer of Org1MSP: %+v", res.ProposalResponses)
+	}
+
+	if _, err := client.Query(req, WithTargetFilter(func(Peer) bool { return false })); err == nil {
+		t.Error("should have returned an error, the target filter rejects all the peers")
+	}
 }
 
 func queryBlock(t *testing.T, client *Client) {
 	if _, err := client.QueryBlock(1); err != nil {
 		t.Fatal(err)
 	}
+
+	if _, err := client.QueryBlock(1, WithTargetPeers("peer0.org1.dummy.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.QueryBlock(1, WithTargetPeers("peer9.org1.dummy.com")); err == nil {
+		t.Error("should have returned an error, peer 'peer9.org1.dummy.com' is unknown")
+	}
 }
 
 func queryBlockByTxID(t *testing.T, client *Client) {
-	if _, err := client.QueryBlockByTxID(txID); err != nil {
+	block, err := client.QueryBlockByTxID(txID)
+	if err != nil {
 		t.Fatal(err)
 	}
+
+	transactions, err := block.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, tx := range transactions {
+		if tx.TxID == txID && len(tx.Actions) > 0 && tx.Actions[0].ChaincodeName == client.Config().Chaincodes[0].Name {
+			found = true
+
+			if tx.ValidationCode != TxValidationCodeValid {
+				t.Errorf("transaction %s should be valid but got %s", txID, tx.ValidationCode)
+			}
+		}
+	}
+
+	if !found {
+		t.Errorf("transaction %s should have been decoded from its block", txID)
+	}
+}
+
+func queryTransaction(t *testing.T, client *Client) {
+	processedTransaction, err := client.QueryTransaction(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processedTransaction.ValidationCode != TxValidationCodeValid || processedTransaction.Transaction.TxID != txID {
+		t.Errorf("unexpected processed transaction: %+v", processedTransaction)
+	}
+
+	block, err := client.QueryBlockByTxID(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processedTransaction.BlockNumber != block.Header.Number {
+		t.Errorf("transaction %s should be in block %d but got %d", txID, block.Header.Number, processedTransaction.BlockNumber)
+	}
+
+	if _, err := client.QueryTransaction("dummy"); err == nil {
+		t.Error("should have returned an error when querying transaction: transaction 'dummy' does not exist")
+	}
+}
+
+func queryChannelConfig(t *testing.T, client *Client) {
+	channelConfig, err := client.QueryChannelConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if channelConfig.ChannelID != client.Config().Channels[0].Name || channelConfig.Application == nil || channelConfig.Orderer == nil {
+		t.Fatalf("unexpected channel config: %+v", channelConfig)
+	}
+
+	mspIDs := make(map[string]bool)
+	for _, organization := range channelConfig.Application.Organizations {
+		mspIDs[organization.MSPID] = len(organization.RootCerts) > 0
+	}
+
+	if !mspIDs["Org1MSP"] || !mspIDs["Org2MSP"] {
+		t.Errorf("channel should have Org1MSP and Org2MSP as members with their root certificates: %v", mspIDs)
+	}
+
+	if len(channelConfig.Orderer.OrdererType) == 0 || len(channelConfig.Orderer.Organizations) == 0 {
+		t.Errorf("unexpected orderer config: %+v", channelConfig.Orderer)
+	}
 }
 
 func queryInfo(t *testing.T, client *Client) {
@@ -183,6 +311,368 @@ func chaincodeEventTimeout(t *testing.T, client *Client) {
 	client.UnregisterChaincodeEvent("foo")
 }
 
+func registerBlockEvent(t *testing.T, client *Client) {
+	blocks, blockRegistration, err := client.RegisterBlockEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filteredBlocks, filteredBlockRegistration, err := client.RegisterFilteredBlockEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &ChaincodeRequest{
+		ChaincodeID: client.Config().Chaincodes[0].Name,
+		Function:    "Store",
+		Args:        []string{"asset-block-event", `{"content": "this is a block event test"}`},
+	}
+
+	res, err := client.Invoke(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case block := <-blocks:
+		if block == nil || block.Header.Number == 0 || len(block.Data.Data) == 0 {
+			t.Errorf("unexpected block received: %+v", block)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("should have received a block event")
+	}
+
+	found := false
+	timeout := time.After(5 * time.Second)
+	for !found {
+		select {
+		case filteredBlock := <-filteredBlocks:
+			for _, tx := range filteredBlock.FilteredTransactions {
+				if tx.TxID == res.TransactionID {
+					found = true
+
+					if tx.TxValidationCode != TxValidationCodeValid {
+						t.Errorf("transaction '%s' should be valid but got %s", tx.TxID, tx.TxValidationCode)
+					}
+				}
+			}
+		case <-timeout:
+			t.Fatal("should have received a filtered block event containing the transaction")
+		}
+	}
+
+	blockRegistration.Unregister()
+	blockRegistration.Unregister()
+	filteredBlockRegistration.Unregister()
+
+	if _, ok := <-blocks; ok {
+		t.Error("block event channel should have been closed")
+	}
+
+	if _, ok := <-filteredBlocks; ok {
+		t.Error("filtered block event channel should have been closed")
+	}
+}
+
+func replayEvents(t *testing.T, client *Client) {
+	events, err := client.RegisterChaincodeEvent(client.Config().Chaincodes[0].Name, "test", WithSeekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.EventName != "test" || string(event.Payload) != "this is a message test" {
+			t.Errorf("unexpected chaincode event replayed: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("should have replayed the chaincode event")
+	}
+
+	if err := client.UnregisterChaincodeEvent("test"); err != nil {
+		t.Error(err)
+	}
+
+	block, err := client.QueryBlockByTxID(txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txStatus, registration, err := client.RegisterTxStatusEvent(txID, WithStartBlock(block.Header.Number))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case status := <-txStatus:
+		if status.TxID != txID || status.BlockNumber != block.Header.Number || status.TxValidationCode != TxValidationCodeValid {
+			t.Errorf("unexpected transaction status replayed: %+v", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("should have replayed the transaction status event")
+	}
+
+	registration.Unregister()
+
+	blocks, registration, err := client.RegisterBlockEvent(WithSeekNewest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case block := <-blocks:
+		if block == nil {
+			t.Error("should have received the newest block")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("should have received the newest block")
+	}
+
+	registration.Unregister()
+}
+
+func checkpointedChaincodeEvent(t *testing.T, client *Client) {
+	checkpointer := NewInMemoryCheckpointer()
+	chaincodeID := client.Config().Chaincodes[0].Name
+
+	events, err := client.RegisterChaincodeEvent(chaincodeID, "test", WithSeekOldest(), WithCheckpointer(checkpointer, "checkpoint-test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.EventName != "test" || string(event.Payload) != "this is a message test" {
+			t.Errorf("unexpected chaincode event replayed: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("should have replayed the chaincode event")
+	}
+
+	if err := client.UnregisterChaincodeEvent("test"); err != nil {
+		t.Error(err)
+	}
+
+	channelID := client.Config().Channels[0].Name
+	if checkpoint, _ := checkpointer.Load(channelID, "checkpoint-test"); checkpoint == nil {
+		t.Fatal("checkpoint should have been saved")
+	}
+
+	events, err = client.RegisterChaincodeEvent(chaincodeID, "test", WithSeekOldest(), WithCheckpointer(checkpointer, "checkpoint-test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("chaincode event already delivered should have been skipped: %+v", event)
+	case <-time.After(3 * time.Second):
+	}
+
+	if err := client.UnregisterChaincodeEvent("test"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := client.RegisterChaincodeEvent(chaincodeID, "(", WithCheckpointer(checkpointer, "checkpoint-test")); err == nil {
+		t.Error("should have returned an error when registering chaincode event: invalid event filter")
+	}
+}
+
+func multipleChaincodeEventSubscribers(t *testing.T, client *Client) {
+	chaincodeID := client.Config().Chaincodes[0].Name
+
+	first, firstRegistration, err := client.RegisterChaincodeEventWithRegistration(chaincodeID, "test", WithSeekOldest(), WithSubscriber("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, secondRegistration, err := client.RegisterChaincodeEventWithRegistration(chaincodeID, "test", WithSeekOldest(), WithSubscriber("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.RegisterChaincodeEvent(chaincodeID, "test", WithSubscriber("second")); err == nil {
+		t.Error("should have returned an error when registering chaincode event: event filter 'test' already registered by subscriber 'second'")
+	}
+
+	for _, events := range []<-chan *ChaincodeEvent{first, second} {
+		select {
+		case event := <-events:
+			if event.EventName != "test" || string(event.Payload) != "this is a message test" {
+				t.Errorf("unexpected chaincode event replayed: %+v", event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("each subscriber should have received the chaincode event")
+		}
+	}
+
+	if err := client.UnregisterChaincodeEvent("test", WithSubscriber("first")); err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := <-first; ok {
+		t.Error("event channel of the first subscriber should have been closed")
+	}
+
+	if secondRegistration.Err() != nil {
+		t.Errorf("registration of the second subscriber should still be ongoing: %v", secondRegistration.Err())
+	}
+
+	firstRegistration.Unregister()
+	secondRegistration.Unregister()
+	if _, ok := <-second; ok {
+		t.Error("event channel of the second subscriber should have been closed")
+	}
+}
+
+func submitAsync(t *testing.T, client *Client) {
+	commits := make([]*Commit, 0, 3)
+	for i := 0; i < cap(commits); i++ {
+		req := &ChaincodeRequest{
+			ChaincodeID: client.Config().Chaincodes[0].Name,
+			Function:    "Store",
+			Args:        []string{fmt.Sprintf("asset-async-%d", i), `{"content": "this is an async content test"}`},
+		}
+
+		commit, err := client.SubmitAsync(req, WithCommitTimeout(20*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(commit.TxID()) == 0 {
+			t.Error("transaction ID should not be empty")
+		}
+
+		commits = append(commits, commit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, commit := range commits {
+		txStatus, err := commit.Wait(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if txStatus.TxID != commit.TxID() || txStatus.TxValidationCode != TxValidationCodeValid || txStatus.BlockNumber == 0 {
+			t.Errorf("unexpected transaction status: %+v", txStatus)
+		}
+
+		if commit.Status() != txStatus {
+			t.Errorf("status should be the one returned by Wait: %+v", commit.Status())
+		}
+	}
+
+	req := &ChaincodeRequest{
+		ChaincodeID: client.Config().Chaincodes[0].Name,
+		Function:    "Dummy",
+	}
+
+	if _, err := client.SubmitAsync(req); err == nil {
+		t.Errorf("should have returned an error when submitting transaction %+v", req)
+	}
+}
+
+func endorseAndSubmit(t *testing.T, client *Client) {
+	req := &ChaincodeRequest{
+		ChaincodeID: client.Config().Chaincodes[0].Name,
+		Function:    "Store",
+		Args:        []string{"asset-endorsed", `{"content": "this is an endorsed content test"}`},
+	}
+
+	tx, err := client.Endorse(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tx.TransactionID) == 0 || len(tx.Proposal) == 0 || len(tx.Endorsements) == 0 || len(tx.Endorsements) != len(tx.Response.ProposalResponses) {
+		t.Fatalf("unexpected endorsed transaction: %+v", tx)
+	}
+
+	for _, proposalResponse := range tx.Response.ProposalResponses {
+		if !bytes.Equal(proposalResponse.Payload, tx.Response.ProposalResponses[0].Payload) {
+			t.Errorf("endorsing peers should have returned the same payload: %+v", tx.Response.ProposalResponses)
+		}
+	}
+
+	res, err := client.Submit(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.TransactionID != tx.TransactionID || res.TxValidationCode != TxValidationCodeValid || res.BlockNumber == 0 {
+		t.Errorf("unexpected transaction response: %+v", res)
+	}
+
+	if _, err := client.Submit(nil); err == nil {
+		t.Error("should have returned an error when submitting a nil transaction")
+	}
+
+	if _, err := client.Submit(&EndorsedTransaction{ChannelID: tx.ChannelID}); err == nil {
+		t.Error("should have returned an error when submitting a transaction without endorsement")
+	}
+}
+
+func detachChannel(t *testing.T, client *Client) {
+	channel := client.Config().Channels[0]
+
+	detachedClient, err := NewClient(client.Config(), WithAutoAttachChannels())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks, _, err := detachedClient.RegisterBlockEvent(WithChannelContext(channel.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := detachedClient.RegisterChaincodeEvent(client.Config().Chaincodes[0].Name, "detach", WithChannelContext(channel.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := detachedClient.DetachChannel(channel.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := <-blocks; ok {
+		t.Error("block event channel should have been closed")
+	}
+
+	if _, ok := <-events; ok {
+		t.Error("chaincode event channel should have been closed")
+	}
+
+	if err := detachedClient.DetachChannel(channel.Name); err == nil {
+		t.Errorf("should have returned an error, channel '%s' is not attached anymore", channel.Name)
+	}
+
+	if _, err := detachedClient.QueryInfo(WithChannelContext(channel.Name)); err == nil {
+		t.Errorf("should have returned an error, channel '%s' is not attached anymore", channel.Name)
+	}
+
+	if err := detachedClient.AttachChannel(channel.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks, _, err = detachedClient.RegisterBlockEvent(WithChannelContext(channel.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detachedClient.Close()
+	detachedClient.Close()
+
+	if _, ok := <-blocks; ok {
+		t.Error("block event channel should have been closed when closing the client")
+	}
+
+	if err := detachedClient.AttachChannel(channel.Name); err == nil {
+		t.Error("should have returned an error, client is closed")
+	}
+}
+
 func chaincodePrivateDataCollection(t *testing.T, client1, client2 *Client) {
 	req := &ChaincodeRequest{
 		ChaincodeID: client1.Config().Chaincodes[0].Name,
@@ -229,6 +719,10 @@ func chaincodeOpsFailureCases(t *testing.T, client *Client) {
 		t.Errorf("should have returned an error when invoking chaincode %+v", req)
 	}
 
+	if _, err := client.Invoke(req, WithRetryPolicy(RetryPolicy{Attempts: 2, RetryOn: RetryTransientErrors})); err == nil || IsRetryable(err) {
+		t.Errorf("should have returned a non retryable error when invoking chaincode %+v: %v", req, err)
+	}
+
 	if _, err := client.Invoke(req, WithChannelContext("dummy")); err == nil {
 		t.Error("should have returned an error when invoking chaincode: invalid channel context (dummy)")
 	}
@@ -261,6 +755,14 @@ func chaincodeOpsFailureCases(t *testing.T, client *Client) {
 		t.Error("should have returned an error when querying block by hash: invalid channel context (dummy)")
 	}
 
+	if _, err := client.QueryTransaction("dummy", WithChannelContext("dummy")); err == nil {
+		t.Error("should have returned an error when querying transaction: invalid channel context (dummy)")
+	}
+
+	if _, err := client.QueryChannelConfig(WithChannelContext("dummy")); err == nil {
+		t.Error("should have returned an error when querying channel config: invalid channel context (dummy)")
+	}
+
 	if _, err := client.QueryInfo(WithChannelContext("dummy")); err == nil {
 		t.Error("should have returned an error when querying info: invalid channel context (dummy)")
 	}
@@ -269,6 +771,32 @@ func chaincodeOpsFailureCases(t *testing.T, client *Client) {
 		t.Error("should have returned an error when registering chaincode event: invalid channel context (dummy)")
 	}
 
+	if _, _, err := client.RegisterBlockEvent(WithChannelContext("dummy")); err == nil {
+		t.Error("should have returned an error when registering block event: invalid channel context (dummy)")
+	}
+
+	if _, _, err := client.RegisterFilteredBlockEvent(WithChannelContext("dummy")); err == nil {
+		t.Error("should have returned an error when registering filtered block event: invalid channel context (dummy)")
+	}
+
+	if _, _, err := client.RegisterTxStatusEvent("dummy", WithChannelContext("dummy")); err == nil {
+		t.Error("should have returned an error when registering transaction status event: invalid channel context (dummy)")
+	}
+
+	txStatus, txStatusRegistration, err := client.RegisterTxStatusEvent("dummy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := client.RegisterTxStatusEvent("dummy"); err == nil {
+		t.Error("should have returned an error when registering transaction status event: transaction 'dummy' already registered")
+	}
+
+	txStatusRegistration.Unregister()
+	if _, ok := <-txStatus; ok {
+		t.Error("transaction status event channel should have been closed")
+	}
+
 	if err := client.UnregisterChaincodeEvent("dummy", WithChannelContext("dummy")); err == nil {
 		t.Error("should have returned an error when unregistering chaincode event: invalid channel context (dummy)")
 	}
@@ -278,7 +806,11 @@ func chaincodeOpsFailureCases(t *testing.T, client *Client) {
 	}
 
 	if _, err := client.RegisterChaincodeEvent(client.Config().Chaincodes[0].Name, "eventFilter"); err == nil {
-		t.Errorf("should have returned an error when registering chaincode event: chaincode %s does not exist", client.Config().Chaincodes[0].Name)
+		t.Errorf("should have returned an error when registering chaincode event: event filter 'eventFilter' of chaincode %s already registered", client.Config().Chaincodes[0].Name)
+	}
+
+	if _, err := client.RegisterChaincodeEvent(client.Config().Chaincodes[0].Name, "(", WithSubscriber("dummy")); err == nil {
+		t.Error("should have returned an error when registering chaincode event: invalid event filter")
 	}
 
 	if err := client.UnregisterChaincodeEvent("eventFilter"); err != nil {
@@ -286,12 +818,103 @@ func chaincodeOpsFailureCases(t *testing.T, client *Client) {
 	}
 }
 
+func chaincodeOpsWithCanceledContext(t *testing.T, client *Client) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := &ChaincodeRequest{
+		ChaincodeID: client.Config().Chaincodes[0].Name,
+		Function:    "Query",
+		Args:        []string{"asset-test"},
+	}
+
+	if _, err := client.InvokeContext(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v when invoking chaincode with a canceled context but got %v", context.Canceled, err)
+	}
+
+	if _, err := client.QueryContext(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v when querying chaincode with a canceled context but got %v", context.Canceled, err)
+	}
+
+	if _, err := client.QueryBlockContext(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v when querying block with a canceled context but got %v", context.Canceled, err)
+	}
+
+	if _, err := client.QueryInfoContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v when querying info with a canceled context but got %v", context.Canceled, err)
+	}
+}
+
 func testConvertBlockchainInfo(t *testing.T) {
 	if bci := convertBlockchainInfo(nil); bci != nil {
 		t.Error("blockchain info should be nil")
 	}
 }
 
+func testConvertFilteredBlock(t *testing.T) {
+	if fb := convertFilteredBlock(nil); fb != nil {
+		t.Error("filtered block should be nil")
+	}
+
+	event := &fab.FilteredBlockEvent{
+		FilteredBlock: &peer.FilteredBlock{
+			ChannelId: "channel",
+			Number:    42,
+			FilteredTransactions: []*peer.FilteredTransaction{
+				{
+					Txid:             "txID",
+					Type:             common.HeaderType_ENDORSER_TRANSACTION,
+					TxValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT,
+					Data: &peer.FilteredTransaction_TransactionActions{
+						TransactionActions: &peer.FilteredTransactionActions{
+							ChaincodeActions: []*peer.FilteredChaincodeAction{
+								{ChaincodeEvent: &peer.ChaincodeEvent{TxId: "txID", ChaincodeId: "cc", EventName: "event"}},
+								{},
+							},
+						},
+					},
+				},
+			},
+		},
+		SourceURL: "peer0",
+	}
+
+	fb := convertFilteredBlock(event)
+	if fb.ChannelID != "channel" || fb.Number != 42 || len(fb.FilteredTransactions) != 1 {
+		t.Fatalf("unexpected filtered block: %+v", fb)
+	}
+
+	tx := fb.FilteredTransactions[0]
+	if tx.TxID != "txID" || tx.Type != "ENDORSER_TRANSACTION" || tx.TxValidationCode.String() != "MVCC_READ_CONFLICT" {
+		t.Errorf("unexpected filtered transaction: %+v", tx)
+	}
+
+	if len(tx.ChaincodeEvents) != 1 || tx.ChaincodeEvents[0].EventName != "event" || tx.ChaincodeEvents[0].BlockNumber != 42 {
+		t.Errorf("unexpected chaincode events: %+v", tx.ChaincodeEvents)
+	}
+}
+
+func testConvertTxStatusEvent(t *testing.T) {
+	if e := convertTxStatusEvent(nil); e != nil {
+		t.Error("transaction status event should be nil")
+	}
+
+	e := convertTxStatusEvent(&fab.TxStatusEvent{
+		TxID:             "txID",
+		TxValidationCode: peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE,
+		BlockNumber:      42,
+		SourceURL:        "peer0",
+	})
+
+	if e.TxID != "txID" || e.BlockNumber != 42 || e.SourceURL != "peer0" {
+		t.Errorf("unexpected transaction status event: %+v", e)
+	}
+
+	if e.TxValidationCode == TxValidationCodeValid || e.TxValidationCode.String() != "ENDORSEMENT_POLICY_FAILURE" {
+		t.Errorf("unexpected validation code: %s", e.TxValidationCode)
+	}
+}
+
 func testConvertChaincodeRequest(t *testing.T) {
 	req := &ChaincodeRequest{
 		ChaincodeID: "",
@@ -313,3 +936,218 @@ func testConvertChaincodeRequest(t *testing.T) {
 
 	r = convertChaincodeRequest(nil)
 }
+
+func testConvertChaincodeTransactionResponse(t *testing.T) {
+	response := channel.Response{
+		TransactionID:    "txid",
+		TxValidationCode: peer.TxValidationCode_VALID,
+		ChaincodeStatus:  200,
+		Payload:          []byte("result"),
+		Responses: []*fab.TransactionProposalResponse{
+			{
+				Endorser:        "peer0.org1.dummy.com:7051",
+				Status:          200,
+				ChaincodeStatus: 200,
+				ProposalResponse: &peer.ProposalResponse{
+					Payload:  mustMarshal(t, &peer.ProposalResponsePayload{Extension: mustMarshal(t, newTestChaincodeAction(t))}),
+					Response: &peer.Response{Status: 200, Message: "OK", Payload: []byte("result")},
+				},
+			},
+		},
+	}
+
+	res := convertChaincodeTransactionResponse(response, 42)
+
+	if res.TransactionID != "txid" || res.TxValidationCode != TxValidationCodeValid || res.BlockNumber != 42 || string(res.Payload) != "result" {
+		t.Errorf("unexpected transaction response: %+v", res)
+	}
+
+	if len(res.ProposalResponses) != 1 || res.ProposalResponses[0].Endorser != "peer0.org1.dummy.com:7051" || res.ProposalResponses[0].Message != "OK" {
+		t.Errorf("unexpected proposal responses: %+v", res.ProposalResponses)
+	}
+
+	if len(res.ReadWriteSets) != 1 || res.ReadWriteSets[0].Namespace != "mycc" || len(res.ReadWriteSets[0].Writes) != 2 {
+		t.Errorf("unexpected read/write sets: %+v", res.ReadWriteSets)
+	}
+
+	if res.Event == nil || res.Event.EventName != "event" || res.Event.TxID != "txid" || res.Event.BlockNumber != 42 {
+		t.Errorf("unexpected chaincode event: %+v", res.Event)
+	}
+
+	if res := convertChaincodeTransactionResponse(channel.Response{}, 0); len(res.ProposalResponses) != 0 || res.Event != nil {
+		t.Errorf("unexpected transaction response: %+v", res)
+	}
+}
+
+type roleFilter struct{}
+
+func (roleFilter) Accept(peer fab.Peer) bool {
+	return peer.URL() != "peer1.org1.dummy.com:8051"
+}
+
+func TestTargetFilter(t *testing.T) {
+	org1Peer0 := mocks.NewMockPeer("peer0.org1.dummy.com", "peer0.org1.dummy.com:7051")
+	org1Peer0.SetProperties(fab.Properties{fab.PropertyLedgerHeight: uint64(42)})
+	org1Peer1 := mocks.NewMockPeer("peer1.org1.dummy.com", "peer1.org1.dummy.com:8051")
+	org2Peer0 := mocks.NewMockPeer("peer0.org2.dummy.com", "peer0.org2.dummy.com:9051")
+	org2Peer0.SetMSPID("Org2MSP")
+
+	if targetFilter := newTargetFilter(nil, &options{}); targetFilter != nil {
+		t.Errorf("should not have returned a filter, no target organization nor filter given: %v", targetFilter)
+	}
+
+	if targetFilter := newTargetFilter(roleFilter{}, &options{}); targetFilter != (roleFilter{}) {
+		t.Errorf("should have returned the role filter, no target organization nor filter given: %v", targetFilter)
+	}
+
+	var filtered []Peer
+	targetFilter := newTargetFilter(roleFilter{}, &options{
+		targetOrgs: []string{"Org1MSP"},
+		targetFilter: func(peer Peer) bool {
+			filtered = append(filtered, peer)
+			return true
+		},
+	})
+
+	if !targetFilter.Accept(org1Peer0) || targetFilter.Accept(org1Peer1) || targetFilter.Accept(org2Peer0) {
+		t.Error("should only accept peer0.org1.dummy.com")
+	}
+
+	if len(filtered) != 1 || filtered[0] != (Peer{URL: "peer0.org1.dummy.com:7051", MSPID: "Org1MSP", LedgerHeight: 42}) {
+		t.Errorf("the filter should only have been called for peer0.org1.dummy.com: %+v", filtered)
+	}
+
+	targetFilter = newTargetFilter(nil, &options{targetFilter: func(peer Peer) bool { return peer.MSPID == "Org2MSP" }})
+	if targetFilter.Accept(org1Peer0) || !targetFilter.Accept(org2Peer0) {
+		t.Error("should only accept the peers of Org2MSP")
+	}
+
+	if opts := convertOptions(context.Background(), roleFilter{}, WithTargetOrgs("Org1MSP")); len(opts) != 2 {
+		t.Errorf("should have returned the parent context and the target filter options: %d", len(opts))
+	}
+
+	if opts := convertLedgerOptions(context.Background()); len(opts) != 1 {
+		t.Errorf("should only have returned the parent context option: %d", len(opts))
+	}
+}
+
+func newTestProposal(t *testing.T, channelID string, creator []byte) *fab.TransactionProposal {
+	invocationSpec := &peer.ChaincodeInvocationSpec{
+		ChaincodeSpec: &peer.ChaincodeSpec{
+			ChaincodeId: &peer.ChaincodeID{Name: "mycc"},
+			Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("Store"), []byte("asset")}},
+		},
+	}
+
+	header := &common.Header{
+		ChannelHeader:   mustMarshal(t, &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: channelID, TxId: "txid"}),
+		SignatureHeader: mustMarshal(t, &common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}),
+	}
+
+	return &fab.TransactionProposal{
+		TxnID: "txid",
+		Proposal: &peer.Proposal{
+			Header:  mustMarshal(t, header),
+			Payload: mustMarshal(t, &peer.ChaincodeProposalPayload{Input: mustMarshal(t, invocationSpec)}),
+		},
+	}
+}
+
+func TestEndorsedTransaction(t *testing.T) {
+	identity := mockmsp.NewMockSigningIdentity("user1", "Org1MSP")
+
+	creator, err := identity.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelContext := mocks.NewMockChannelContext(mocks.NewMockContext(identity), "mychannel")
+
+	response := channel.Response{
+		Proposal:        newTestProposal(t, "mychannel", creator),
+		TransactionID:   "txid",
+		ChaincodeStatus: 200,
+		Payload:         []byte("result"),
+		Responses: []*fab.TransactionProposalResponse{
+			{
+				Endorser:        "peer0.org1.dummy.com:7051",
+				Status:          200,
+				ChaincodeStatus: 200,
+				ProposalResponse: &peer.ProposalResponse{
+					Payload:     mustMarshal(t, &peer.ProposalResponsePayload{Extension: mustMarshal(t, newTestChaincodeAction(t))}),
+					Response:    &peer.Response{Status: 200, Message: "OK", Payload: []byte("result")},
+					Endorsement: &peer.Endorsement{Endorser: []byte("endorser"), Signature: []byte("signature")},
+				},
+			},
+		},
+	}
+
+	tx, err := convertEndorsedTransaction(channelContext, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tx.TransactionID != "txid" || tx.ChannelID != "mychannel" || len(tx.Endorsements) != 1 || string(tx.Response.Payload) != "result" {
+		t.Errorf("unexpected endorsed transaction: %+v", tx)
+	}
+
+	endorsedProposal := &peer.Proposal{}
+	if err := proto.Unmarshal(tx.Proposal, endorsedProposal); err != nil {
+		t.Fatal(err)
+	}
+
+	if !proto.Equal(endorsedProposal, response.Proposal.Proposal) {
+		t.Error("proposal should be the one sent to the endorsing peers")
+	}
+
+	// the endorsed transaction can be persisted and submitted later on
+	persisted, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &EndorsedTransaction{}
+	if err := json.Unmarshal(persisted, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	request, proposal, responses, err := convertEndorsements(channelContext, restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.ChaincodeID != "mycc" || request.Fcn != "Store" || len(request.Args) != 1 || string(request.Args[0]) != "asset" {
+		t.Errorf("unexpected request: %+v", request)
+	}
+
+	if proposal.TxnID != "txid" || !proto.Equal(proposal.Proposal, response.Proposal.Proposal) {
+		t.Errorf("unexpected proposal: %+v", proposal)
+	}
+
+	if len(responses) != 1 || responses[0].Endorser != "peer0.org1.dummy.com:7051" || responses[0].Status != 200 || responses[0].ChaincodeStatus != 200 {
+		t.Errorf("unexpected proposal responses: %+v", responses)
+	}
+
+	if !proto.Equal(responses[0].ProposalResponse, response.Responses[0].ProposalResponse) {
+		t.Errorf("unexpected proposal response: %+v", responses[0].ProposalResponse)
+	}
+
+	if _, _, _, err := convertEndorsements(mocks.NewMockChannelContext(mocks.NewMockContext(identity), "otherchannel"), restored); err == nil {
+		t.Error("should have returned an error, the transaction has been endorsed on channel 'mychannel'")
+	}
+
+	otherIdentity := mockmsp.NewMockSigningIdentity("user2", "Org1MSP")
+	if _, _, _, err := convertEndorsements(mocks.NewMockChannelContext(mocks.NewMockContext(otherIdentity), "mychannel"), restored); err == nil {
+		t.Error("should have returned an error, the transaction has been endorsed for another identity")
+	}
+
+	restored.Endorsements = append(restored.Endorsements, Endorsement{Endorser: "peer1.org1.dummy.com:7051", ProposalResponse: restored.Endorsements[0].ProposalResponse})
+	if _, _, _, err := convertEndorsements(channelContext, restored); err == nil {
+		t.Error("should have returned an error, the proposal responses have been endorsed by the same identity")
+	}
+
+	restored.Endorsements = nil
+	if _, _, _, err := convertEndorsements(channelContext, restored); err == nil {
+		t.Error("should have returned an error, the transaction has not been endorsed")
+	}
+}
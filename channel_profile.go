@@ -0,0 +1,304 @@
+package fabclient
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/genesisconfig"
+)
+
+const (
+	defaultCapability   = "V2_0"
+	defaultMSPType      = "bccsp"
+	defaultBatchTimeout = 2 * time.Second
+)
+
+var defaultBatchSize = BatchSize{
+	MaxMessageCount:   10,
+	AbsoluteMaxBytes:  99 * 1024 * 1024,
+	PreferredMaxBytes: 512 * 1024,
+}
+
+// ChannelProfile describes a channel to create, as a profile of configtx.yaml does. It allows to generate the channel
+// creation transaction and the application channel genesis block without the configtxgen binary. Unset capabilities,
+// policies and orderer settings default to the ones of the Fabric sample configtx.yaml.
+type ChannelProfile struct {
+	Consortium   string              `json:"consortium,omitempty" yaml:"consortium,omitempty"`
+	Application  *ApplicationProfile `json:"application" yaml:"application"`
+	Orderer      *OrdererProfile     `json:"orderer,omitempty" yaml:"orderer,omitempty"`
+	Capabilities []string            `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Policies     map[string]*Policy  `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// ApplicationProfile describes the application organizations of a channel to create.
+type ApplicationProfile struct {
+	Organizations []*OrganizationProfile `json:"organizations" yaml:"organizations"`
+	Capabilities  []string               `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Policies      map[string]*Policy     `json:"policies,omitempty" yaml:"policies,omitempty"`
+	ACLs          map[string]string      `json:"acls,omitempty" yaml:"acls,omitempty"`
+}
+
+// OrdererProfile describes the ordering service of a channel to create. It is only required to generate the genesis
+// block of an application channel. The batch timeout is expressed as a duration string (i.e. "2s").
+type OrdererProfile struct {
+	OrdererType   string                 `json:"ordererType,omitempty" yaml:"ordererType,omitempty"`
+	Addresses     []string               `json:"addresses" yaml:"addresses"`
+	BatchTimeout  string                 `json:"batchTimeout,omitempty" yaml:"batchTimeout,omitempty"`
+	BatchSize     *BatchSize             `json:"batchSize,omitempty" yaml:"batchSize,omitempty"`
+	Consenters    []*ConsenterProfile    `json:"consenters" yaml:"consenters"`
+	Organizations []*OrganizationProfile `json:"organizations" yaml:"organizations"`
+	Capabilities  []string               `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+	Policies      map[string]*Policy     `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// ConsenterProfile describes a member of the consensus of a Raft ordering service, its TLS certificates are read from
+// their paths.
+type ConsenterProfile struct {
+	Host              string `json:"host" yaml:"host"`
+	Port              uint32 `json:"port" yaml:"port"`
+	ClientTLSCertPath string `json:"clientTLSCertPath" yaml:"clientTLSCertPath"`
+	ServerTLSCertPath string `json:"serverTLSCertPath" yaml:"serverTLSCertPath"`
+}
+
+// OrganizationProfile describes an organization of a channel to create. Its MSP is read from the MSP directory, laid out
+// as generated by cryptogen or the Fabric CA client.
+type OrganizationProfile struct {
+	Name        string             `json:"name" yaml:"name"`
+	MSPID       string             `json:"mspID" yaml:"mspID"`
+	MSPDir      string             `json:"mspDir" yaml:"mspDir"`
+	Policies    map[string]*Policy `json:"policies,omitempty" yaml:"policies,omitempty"`
+	AnchorPeers []*AnchorPeer      `json:"anchorPeers,omitempty" yaml:"anchorPeers,omitempty"`
+}
+
+// NewChannelCreationTx generates the transaction creating the given channel through the ordering system channel, as
+// `configtxgen -outputCreateChannelTx` does. The profile must define the consortium of the channel.
+func NewChannelCreationTx(channelID string, profile *ChannelProfile) ([]byte, error) {
+	if profile == nil || profile.Consortium == "" {
+		return nil, fmt.Errorf("failed to generate channel creation transaction of channel '%s': consortium is required", channelID)
+	}
+
+	genesisProfile, err := profile.toGenesisProfile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate channel creation transaction of channel '%s': %w", channelID, err)
+	}
+
+	tx, err := resource.CreateChannelCreateTx(genesisProfile, nil, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate channel creation transaction of channel '%s': %w", channelID, err)
+	}
+
+	return tx, nil
+}
+
+// NewChannelGenesisBlock generates the genesis block of the given application channel, as `configtxgen -outputBlock`
+// does. It is used to join the orderers to the channel on networks without an ordering system channel (Fabric 2.3+).
+// The profile must define the ordering service.
+func NewChannelGenesisBlock(channelID string, profile *ChannelProfile) ([]byte, error) {
+	if profile == nil || profile.Orderer == nil {
+		return nil, fmt.Errorf("failed to generate genesis block of channel '%s': orderer is required", channelID)
+	}
+
+	genesisProfile, err := profile.toGenesisProfile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate genesis block of channel '%s': %w", channelID, err)
+	}
+
+	block, err := resource.CreateGenesisBlock(genesisProfile, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate genesis block of channel '%s': %w", channelID, err)
+	}
+
+	return block, nil
+}
+
+func (profile *ChannelProfile) toGenesisProfile() (*genesisconfig.Profile, error) {
+	if profile.Application == nil || len(profile.Application.Organizations) == 0 {
+		return nil, fmt.Errorf("at least one application organization is required")
+	}
+
+	genesisProfile := &genesisconfig.Profile{
+		Consortium:   profile.Consortium,
+		Capabilities: toGenesisCapabilities(profile.Capabilities),
+		Policies: toGenesisPolicies(profile.Policies, map[string]*Policy{
+			"Readers": {Type: "IMPLICIT_META", Rule: "ANY Readers"},
+			"Writers": {Type: "IMPLICIT_META", Rule: "ANY Writers"},
+			"Admins":  {Type: "IMPLICIT_META", Rule: "MAJORITY Admins"},
+		}),
+		Application: &genesisconfig.Application{
+			Capabilities: toGenesisCapabilities(profile.Application.Capabilities),
+			ACLs:         profile.Application.ACLs,
+			Policies: toGenesisPolicies(profile.Application.Policies, map[string]*Policy{
+				"Readers":              {Type: "IMPLICIT_META", Rule: "ANY Readers"},
+				"Writers":              {Type: "IMPLICIT_META", Rule: "ANY Writers"},
+				"Admins":               {Type: "IMPLICIT_META", Rule: "MAJORITY Admins"},
+				"LifecycleEndorsement": {Type: "IMPLICIT_META", Rule: "MAJORITY Endorsement"},
+				"Endorsement":          {Type: "IMPLICIT_META", Rule: "MAJORITY Endorsement"},
+			}),
+		},
+	}
+
+	for _, organization := range profile.Application.Organizations {
+		genesisOrganization, err := organization.toGenesisOrganization(defaultApplicationOrganizationPolicies(organization.MSPID))
+		if err != nil {
+			return nil, err
+		}
+
+		genesisProfile.Application.Organizations = append(genesisProfile.Application.Organizations, genesisOrganization)
+	}
+
+	if profile.Orderer == nil {
+		return genesisProfile, nil
+	}
+
+	var err error
+	if genesisProfile.Orderer, err = profile.Orderer.toGenesisOrderer(); err != nil {
+		return nil, err
+	}
+
+	return genesisProfile, nil
+}
+
+func (profile *OrdererProfile) toGenesisOrderer() (*genesisconfig.Orderer, error) {
+	ordererType := profile.OrdererType
+	if ordererType == "" {
+		ordererType = etcdraftConsensusType
+	}
+
+	batchTimeout := defaultBatchTimeout
+	if profile.BatchTimeout != "" {
+		var err error
+		if batchTimeout, err = time.ParseDuration(profile.BatchTimeout); err != nil {
+			return nil, fmt.Errorf("invalid batch timeout '%s': %w", profile.BatchTimeout, err)
+		}
+	}
+
+	batchSize := defaultBatchSize
+	if profile.BatchSize != nil {
+		batchSize = *profile.BatchSize
+	}
+
+	genesisOrderer := &genesisconfig.Orderer{
+		OrdererType:  ordererType,
+		Addresses:    profile.Addresses,
+		BatchTimeout: batchTimeout,
+		BatchSize: genesisconfig.BatchSize{
+			MaxMessageCount:   batchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+			PreferredMaxBytes: batchSize.PreferredMaxBytes,
+		},
+		Capabilities: toGenesisCapabilities(profile.Capabilities),
+		Policies: toGenesisPolicies(profile.Policies, map[string]*Policy{
+			"Readers":         {Type: "IMPLICIT_META", Rule: "ANY Readers"},
+			"Writers":         {Type: "IMPLICIT_META", Rule: "ANY Writers"},
+			"Admins":          {Type: "IMPLICIT_META", Rule: "MAJORITY Admins"},
+			"BlockValidation": {Type: "IMPLICIT_META", Rule: "ANY Writers"},
+		}),
+	}
+
+	if ordererType == etcdraftConsensusType {
+		if len(profile.Consenters) == 0 {
+			return nil, fmt.Errorf("at least one consenter is required by the etcdraft orderer type")
+		}
+
+		// the certificates are read from their paths by the encoder, as configtxgen does
+		genesisOrderer.EtcdRaft = &etcdraft.ConfigMetadata{
+			Options: &etcdraft.Options{
+				TickInterval:         "500ms",
+				ElectionTick:         10,
+				HeartbeatTick:        1,
+				MaxInflightBlocks:    5,
+				SnapshotIntervalSize: 16 * 1024 * 1024,
+			},
+		}
+
+		for _, consenter := range profile.Consenters {
+			genesisOrderer.EtcdRaft.Consenters = append(genesisOrderer.EtcdRaft.Consenters, &etcdraft.Consenter{
+				Host:          consenter.Host,
+				Port:          consenter.Port,
+				ClientTlsCert: []byte(consenter.ClientTLSCertPath),
+				ServerTlsCert: []byte(consenter.ServerTLSCertPath),
+			})
+		}
+	}
+
+	for _, organization := range profile.Organizations {
+		genesisOrganization, err := organization.toGenesisOrganization(map[string]*Policy{
+			"Readers": {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%s.member')", organization.MSPID)},
+			"Writers": {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%s.member')", organization.MSPID)},
+			"Admins":  {Type: "SIGNATURE", Rule: fmt.Sprintf("OR('%s.admin')", organization.MSPID)},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		genesisOrderer.Organizations = append(genesisOrderer.Organizations, genesisOrganization)
+	}
+
+	return genesisOrderer, nil
+}
+
+func (profile *OrganizationProfile) toGenesisOrganization(defaultPolicies map[string]*Policy) (*genesisconfig.Organization, error) {
+	if profile.MSPID == "" || profile.MSPDir == "" {
+		return nil, fmt.Errorf("MSP ID and MSP directory of organization '%s' are required", profile.Name)
+	}
+
+	name := profile.Name
+	if name == "" {
+		name = profile.MSPID
+	}
+
+	genesisOrganization := &genesisconfig.Organization{
+		Name:     name,
+		ID:       profile.MSPID,
+		MSPDir:   profile.MSPDir,
+		MSPType:  defaultMSPType,
+		Policies: toGenesisPolicies(profile.Policies, defaultPolicies),
+	}
+
+	for _, anchorPeer := range profile.AnchorPeers {
+		genesisOrganization.AnchorPeers = append(genesisOrganization.AnchorPeers, &genesisconfig.AnchorPeer{
+			Host: anchorPeer.Host,
+			Port: anchorPeer.Port,
+		})
+	}
+
+	return genesisOrganization, nil
+}
+
+func toGenesisCapabilities(capabilities []string) map[string]bool {
+	if len(capabilities) == 0 {
+		capabilities = []string{defaultCapability}
+	}
+
+	genesisCapabilities := make(map[string]bool, len(capabilities))
+	for _, capability := range capabilities {
+		genesisCapabilities[capability] = true
+	}
+
+	return genesisCapabilities
+}
+
+func toGenesisPolicies(policies, defaultPolicies map[string]*Policy) map[string]*genesisconfig.Policy {
+	if len(policies) == 0 {
+		policies = defaultPolicies
+	}
+
+	// the encoder expects the policy types as written in configtx.yaml
+	genesisPolicies := make(map[string]*genesisconfig.Policy, len(policies))
+	for name, policy := range policies {
+		policyType := policy.Type
+		switch policyType {
+		case common.Policy_SIGNATURE.String():
+			policyType = "Signature"
+		case common.Policy_IMPLICIT_META.String():
+			policyType = "ImplicitMeta"
+		}
+
+		genesisPolicies[name] = &genesisconfig.Policy{Type: policyType, Rule: policy.Rule}
+	}
+
+	return genesisPolicies
+}
@@ -0,0 +1,164 @@
+package fabclient
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+)
+
+func newTestMSPDir(t *testing.T, dir, name string) string {
+	identity, _ := newTestIdentity(t, dir, name)
+
+	certificate, err := ioutil.ReadFile(identity.Certificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mspDir := filepath.Join(dir, name, "msp")
+	for _, subDir := range []string{"cacerts", "tlscacerts"} {
+		if err := os.MkdirAll(filepath.Join(mspDir, subDir), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(mspDir, subDir, "ca.pem"), certificate, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return mspDir
+}
+
+func newTestChannelProfile(t *testing.T, dir string) *ChannelProfile {
+	tlsCert, _ := newTestIdentity(t, dir, "orderer-tls")
+
+	return &ChannelProfile{
+		Consortium: "SampleConsortium",
+		Application: &ApplicationProfile{
+			Organizations: []*OrganizationProfile{
+				{
+					Name:        "Org1MSP",
+					MSPID:       "Org1MSP",
+					MSPDir:      newTestMSPDir(t, dir, "org1"),
+					AnchorPeers: []*AnchorPeer{{Host: "peer0.org1.dummy.com", Port: 7051}},
+				},
+				{MSPID: "Org2MSP", MSPDir: newTestMSPDir(t, dir, "org2")},
+			},
+		},
+		Orderer: &OrdererProfile{
+			Addresses:     []string{"orderer.dummy.com:7050"},
+			BatchTimeout:  "1s",
+			Consenters:    []*ConsenterProfile{{Host: "orderer.dummy.com", Port: 7050, ClientTLSCertPath: tlsCert.Certificate, ServerTLSCertPath: tlsCert.Certificate}},
+			Organizations: []*OrganizationProfile{{Name: "OrdererOrg", MSPID: "OrdererMSP", MSPDir: newTestMSPDir(t, dir, "orderer")}},
+		},
+	}
+}
+
+func TestNewChannelCreationTx(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	profile := newTestChannelProfile(t, dir)
+
+	tx, err := NewChannelCreationTx("channelall", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configUpdateEnvelope, err := decodeConfigUpdateEnvelope(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate); err != nil {
+		t.Fatal(err)
+	}
+
+	application := configUpdate.WriteSet.Groups[applicationGroupKey]
+	if configUpdate.ChannelId != "channelall" || application == nil || len(application.Groups) != 2 {
+		t.Fatalf("unexpected config update: %+v", configUpdate)
+	}
+
+	if _, ok := application.Groups["Org2MSP"]; !ok {
+		t.Error("organization without name should be named after its MSP ID")
+	}
+
+	if _, err := NewChannelCreationTx("channelall", &ChannelProfile{Application: profile.Application}); err == nil {
+		t.Error("should have returned an error, profile has no consortium")
+	}
+
+	profile.Application.Organizations[0].MSPDir = filepath.Join(dir, "dummy")
+	if _, err := NewChannelCreationTx("channelall", profile); err == nil {
+		t.Error("should have returned an error, MSP directory does not exist")
+	}
+}
+
+func TestNewChannelGenesisBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	profile := newTestChannelProfile(t, dir)
+	profile.Consortium = ""
+
+	blockBytes, err := NewChannelGenesisBlock("channelall", profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := extractConfigFromBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelConfig, err := decodeChannelConfig("channelall", block.Header.Number, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if channelConfig.BlockNumber != 0 || len(channelConfig.Capabilities) != 1 || channelConfig.Capabilities[0] != defaultCapability {
+		t.Errorf("unexpected channel config: %+v", channelConfig)
+	}
+
+	if len(channelConfig.Application.Organizations) != 2 || len(channelConfig.Application.Organizations[0].AnchorPeers) != 1 {
+		t.Errorf("unexpected application config: %+v", channelConfig.Application)
+	}
+
+	if policy := channelConfig.Application.Organizations[0].Policies["Admins"]; policy == nil || policy.Rule != "OutOf(1, 'Org1MSP.admin')" {
+		t.Errorf("unexpected organization admins policy: %+v", policy)
+	}
+
+	ordererConfig := channelConfig.Orderer
+	if ordererConfig.OrdererType != etcdraftConsensusType || ordererConfig.BatchSize != defaultBatchSize || ordererConfig.BatchTimeout.String() != "1s" {
+		t.Errorf("unexpected orderer config: %+v", ordererConfig)
+	}
+
+	if len(ordererConfig.Consenters) != 1 || len(ordererConfig.Consenters[0].ServerTLSCert) == 0 || len(ordererConfig.Organizations) != 1 {
+		t.Errorf("unexpected orderer config: %+v", ordererConfig)
+	}
+
+	profile.Orderer.BatchTimeout = "invalid"
+	if _, err := NewChannelGenesisBlock("channelall", profile); err == nil {
+		t.Error("should have returned an error, batch timeout is invalid")
+	}
+
+	if _, err := NewChannelGenesisBlock("channelall", &ChannelProfile{Application: profile.Application}); err == nil {
+		t.Error("should have returned an error, profile has no orderer")
+	}
+}
@@ -0,0 +1,166 @@
+package fabclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint holds the position of the last event processed by a subscription: the number of the block and the index
+// of the transaction within this block.
+type Checkpoint struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	TxIndex     int    `json:"txIndex"`
+}
+
+// Checkpointer records the checkpoints of event subscriptions per channel and subscription.
+type Checkpointer interface {
+	// Load returns the last checkpoint saved for the given channel and subscription, nil if there is none.
+	Load(channelID, subscriptionID string) (*Checkpoint, error)
+	// Save records the checkpoint of the given channel and subscription.
+	Save(channelID, subscriptionID string, checkpoint Checkpoint) error
+}
+
+type checkpoints map[string]map[string]Checkpoint
+
+func (cps checkpoints) load(channelID, subscriptionID string) *Checkpoint {
+	checkpoint, ok := cps[channelID][subscriptionID]
+	if !ok {
+		return nil
+	}
+
+	return &checkpoint
+}
+
+func (cps checkpoints) save(channelID, subscriptionID string, checkpoint Checkpoint) {
+	if _, ok := cps[channelID]; !ok {
+		cps[channelID] = make(map[string]Checkpoint)
+	}
+
+	cps[channelID][subscriptionID] = checkpoint
+}
+
+// InMemoryCheckpointer is a Checkpointer keeping the checkpoints in memory. Checkpoints do not survive a restart of the process.
+type InMemoryCheckpointer struct {
+	checkpoints checkpoints
+	mutex       sync.RWMutex
+}
+
+// NewInMemoryCheckpointer returns an InMemoryCheckpointer instance.
+func NewInMemoryCheckpointer() *InMemoryCheckpointer {
+	return &InMemoryCheckpointer{
+		checkpoints: make(checkpoints),
+	}
+}
+
+var _ Checkpointer = (*InMemoryCheckpointer)(nil)
+
+// Load returns the last checkpoint saved for the given channel and subscription, nil if there is none.
+func (cp *InMemoryCheckpointer) Load(channelID, subscriptionID string) (*Checkpoint, error) {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+
+	return cp.checkpoints.load(channelID, subscriptionID), nil
+}
+
+// Save records the checkpoint of the given channel and subscription.
+func (cp *InMemoryCheckpointer) Save(channelID, subscriptionID string, checkpoint Checkpoint) error {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	cp.checkpoints.save(channelID, subscriptionID, checkpoint)
+	return nil
+}
+
+// FileCheckpointer is a Checkpointer persisting the checkpoints in a JSON file.
+type FileCheckpointer struct {
+	checkpoints checkpoints
+	path        string
+	mutex       sync.RWMutex
+}
+
+// NewFileCheckpointer returns a FileCheckpointer instance. The checkpoints previously saved in the file, if it exists, are loaded.
+func NewFileCheckpointer(path string) (*FileCheckpointer, error) {
+	cp := &FileCheckpointer{
+		checkpoints: make(checkpoints),
+		path:        path,
+	}
+
+	fileAsBytes, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to create file checkpointer: %w", err)
+	}
+
+	if len(fileAsBytes) > 0 {
+		if err := json.Unmarshal(fileAsBytes, &cp.checkpoints); err != nil {
+			return nil, fmt.Errorf("failed to create file checkpointer from file %s: %w", path, err)
+		}
+	}
+
+	return cp, nil
+}
+
+var _ Checkpointer = (*FileCheckpointer)(nil)
+
+// Load returns the last checkpoint saved for the given channel and subscription, nil if there is none.
+func (cp *FileCheckpointer) Load(channelID, subscriptionID string) (*Checkpoint, error) {
+	cp.mutex.RLock()
+	defer cp.mutex.RUnlock()
+
+	return cp.checkpoints.load(channelID, subscriptionID), nil
+}
+
+// Save records the checkpoint of the given channel and subscription. The file is replaced atomically.
+func (cp *FileCheckpointer) Save(channelID, subscriptionID string, checkpoint Checkpoint) error {
+	cp.mutex.Lock()
+	defer cp.mutex.Unlock()
+
+	previous := cp.checkpoints.load(channelID, subscriptionID)
+	cp.checkpoints.save(channelID, subscriptionID, checkpoint)
+
+	if err := cp.write(); err != nil {
+		if previous != nil {
+			cp.checkpoints.save(channelID, subscriptionID, *previous)
+		} else {
+			delete(cp.checkpoints[channelID], subscriptionID)
+		}
+
+		return fmt.Errorf("failed to save checkpoint for subscription '%s' on channel '%s': %w", subscriptionID, channelID, err)
+	}
+
+	return nil
+}
+
+func (cp *FileCheckpointer) write() error {
+	checkpointsAsBytes, err := json.Marshal(cp.checkpoints)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(cp.path), filepath.Base(cp.path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(checkpointsAsBytes); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), cp.path)
+}
@@ -0,0 +1,92 @@
+package fabclient
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testCheckpointer(t *testing.T, checkpointer Checkpointer) {
+	checkpoint, err := checkpointer.Load("channel", "subscription")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if checkpoint != nil {
+		t.Errorf("checkpoint should be nil but got %+v", checkpoint)
+	}
+
+	if err := checkpointer.Save("channel", "subscription", Checkpoint{BlockNumber: 4, TxIndex: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkpointer.Save("channel", "other", Checkpoint{BlockNumber: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint, err = checkpointer.Load("channel", "subscription")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if checkpoint == nil || checkpoint.BlockNumber != 4 || checkpoint.TxIndex != 2 {
+		t.Errorf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	if checkpoint, _ := checkpointer.Load("other", "subscription"); checkpoint != nil {
+		t.Errorf("checkpoint should be nil but got %+v", checkpoint)
+	}
+}
+
+func TestInMemoryCheckpointer(t *testing.T) {
+	testCheckpointer(t, NewInMemoryCheckpointer())
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpointer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "checkpoints.json")
+	checkpointer, err := NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCheckpointer(t, checkpointer)
+
+	checkpointer, err = NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint, err := checkpointer.Load("channel", "subscription")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if checkpoint == nil || checkpoint.BlockNumber != 4 || checkpoint.TxIndex != 2 {
+		t.Errorf("checkpoint should have been loaded from file but got %+v", checkpoint)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("dummy"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileCheckpointer(path); err == nil {
+		t.Error("should have returned an error, invalid checkpoints file")
+	}
+
+	checkpointer.path = filepath.Join(dir, "dummy", "checkpoints.json")
+	if err := checkpointer.Save("channel", "subscription", Checkpoint{BlockNumber: 5}); err == nil {
+		t.Error("should have returned an error, checkpoints directory does not exist")
+	}
+
+	if checkpoint, _ := checkpointer.Load("channel", "subscription"); checkpoint == nil || checkpoint.BlockNumber != 4 {
+		t.Errorf("checkpoint should not have been updated but got %+v", checkpoint)
+	}
+}
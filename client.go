@@ -1,10 +1,12 @@
 package fabclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 )
@@ -26,6 +28,12 @@ func (hls handlers) find(username string) channelHandler {
 	return nil
 }
 
+func (hls handlers) close() {
+	for _, h := range hls {
+		h.handler.close()
+	}
+}
+
 type channelHandlers struct {
 	channelName string
 	handlers    handlers
@@ -51,22 +59,24 @@ type Client struct {
 	msp              membershipServiceProvider
 	resourceManager  resourceManager
 	channelsHandlers channelsHandlers
+	ordererAdmins    []*OrdererAdminClient
 
-	mutex sync.RWMutex
+	closed bool
+	mutex  sync.RWMutex
 }
 
 // NewClientFromConfigFile returns a client instance from a config file.
-func NewClientFromConfigFile(configPath string) (*Client, error) {
+func NewClientFromConfigFile(configPath string, opts ...Option) (*Client, error) {
 	cfg, err := NewConfigFromFile(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewClient(cfg)
+	return NewClient(cfg, opts...)
 }
 
 // NewClient returns a Client instance.
-func NewClient(cfg *Config) (*Client, error) {
+func NewClient(cfg *Config, opts ...Option) (*Client, error) {
 	sdk, err := fabsdk.New(config.FromFile(cfg.ConnectionProfile))
 	if err != nil {
 		return nil, err
@@ -82,45 +92,97 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, err
 	}
 
+	options := &options{}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+
 	adminContext := sdk.Context(fabsdk.WithIdentity(adminIdentity), fabsdk.WithOrg(cfg.Organization))
 
-	rsm, err := newResourceManager(adminContext, adminIdentity)
+	rsm, err := newResourceManager(adminContext, adminIdentity, options.retryPolicy)
 	if err != nil {
 		return nil, err
 	}
 
+	ordererAdmins := make([]*OrdererAdminClient, 0, len(cfg.OrdererAdmins))
+	for _, ordererAdmin := range cfg.OrdererAdmins {
+		ordererAdminClient, err := NewOrdererAdminClient(ordererAdmin)
+		if err != nil {
+			sdk.Close()
+			return nil, err
+		}
+
+		ordererAdmins = append(ordererAdmins, ordererAdminClient)
+	}
+
 	client := &Client{
 		config:           cfg,
 		fabricSDK:        sdk,
 		msp:              msp,
 		resourceManager:  rsm,
 		channelsHandlers: make(channelsHandlers, 0, len(cfg.Channels)),
+		ordererAdmins:    ordererAdmins,
 		mutex:            sync.RWMutex{},
 	}
 
+	if options.autoAttachChannels {
+		if err := client.attachChannels(context.Background()); err != nil {
+			sdk.Close()
+			return nil, err
+		}
+	}
+
 	return client, nil
 }
 
+// attachChannels creates the handlers of the channels listed in the configuration and of the channels joined by the
+// peers of the organization. When a handler cannot be created, the channels attached so far are detached.
+func (client *Client) attachChannels(ctx context.Context) error {
+	channels, err := client.resourceManager.queryChannels(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range client.config.Channels {
+		if !containsString(channels, channel.Name) {
+			channels = append(channels, channel.Name)
+		}
+	}
+
+	attached := make([]string, 0, len(channels))
+	for _, channelID := range channels {
+		if err := client.createChannelHandler(channelID); err != nil {
+			for _, attachedChannelID := range attached {
+				_ = client.DetachChannel(attachedChannelID)
+			}
+
+			return err
+		}
+
+		attached = append(attached, channelID)
+	}
+
+	return nil
+}
+
 func (client *Client) createChannelHandler(channelID string) error {
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
+	if client.closed {
+		return fmt.Errorf("failed to create handler for channel '%s': client is closed", channelID)
+	}
+
 	if handlers := client.channelsHandlers.find(channelID); handlers != nil {
 		return nil
 	}
 
 	handlers := make(handlers, 0, len(client.config.Identities.Users))
 	for _, user := range client.config.Identities.Users {
-		userIdentity, err := client.msp.createSigningIdentity(user.Certificate, user.PrivateKey)
-		if err != nil {
-			return fmt.Errorf("failed to create handler for channel '%s': %w", channelID, err)
-		}
-
-		userContext := client.fabricSDK.ChannelContext(channelID, fabsdk.WithIdentity(userIdentity))
-
-		chHandler, err := newChannelHandler(userContext)
+		chHandler, err := client.createUserChannelHandler(channelID, user)
 		if err != nil {
-			return fmt.Errorf("failed to create handler for channel '%s': %w", channelID, err)
+			handlers.close()
+			return err
 		}
 
 		handlers = append(handlers, handler{
@@ -137,13 +199,68 @@ func (client *Client) createChannelHandler(channelID string) error {
 	return nil
 }
 
-// Close frees up caches and connections being maintained by the SDK.
+func (client *Client) createUserChannelHandler(channelID string, user Identity) (channelHandler, error) {
+	userIdentity, err := client.msp.createSigningIdentity(user.Certificate, user.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create handler for channel '%s': %w", channelID, err)
+	}
+
+	userContext := client.fabricSDK.ChannelContext(channelID, fabsdk.WithIdentity(userIdentity))
+
+	chHandler, err := newChannelHandler(userContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create handler for channel '%s': %w", channelID, err)
+	}
+
+	return chHandler, nil
+}
+
+// createUserHandlers creates the handlers of the given user for every attached channel, in the order of the attached
+// channels. The caller must hold the lock of the client.
+func (client *Client) createUserHandlers(user Identity) ([]channelHandler, error) {
+	userHandlers := make([]channelHandler, 0, len(client.channelsHandlers))
+	for _, c := range client.channelsHandlers {
+		chHandler, err := client.createUserChannelHandler(c.channelName, user)
+		if err != nil {
+			for _, h := range userHandlers {
+				h.close()
+			}
+
+			return nil, err
+		}
+
+		userHandlers = append(userHandlers, chHandler)
+	}
+
+	return userHandlers, nil
+}
+
+// Close unregisters the event registrations of every attached channel, closing their event channels, and frees up
+// caches and connections being maintained by the SDK. It is safe to call it more than once.
 func (client *Client) Close() {
+	client.mutex.Lock()
+	if client.closed {
+		client.mutex.Unlock()
+		return
+	}
+
+	chanHandlers := client.channelsHandlers
+	client.channelsHandlers = nil
+	client.closed = true
+	client.mutex.Unlock()
+
+	for _, c := range chanHandlers {
+		c.handlers.close()
+	}
+
 	client.fabricSDK.Close()
 }
 
 // Config returns the client configuration.
 func (client *Client) Config() *Config {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+
 	config := &Config{
 		Chaincodes:        make([]Chaincode, len(client.config.Chaincodes)),
 		Channels:          make([]Channel, len(client.config.Channels)),
@@ -159,89 +276,547 @@ func (client *Client) Config() *Config {
 			},
 			Users: make([]Identity, len(client.config.Identities.Users)),
 		},
-		Organization: client.config.Organization,
+		OrdererAdmins: make([]OrdererAdmin, len(client.config.OrdererAdmins)),
+		Organization:  client.config.Organization,
 	}
 
 	copy(config.Identities.Users, client.config.Identities.Users)
+	copy(config.OrdererAdmins, client.config.OrdererAdmins)
 	copy(config.Chaincodes, client.config.Chaincodes)
 	copy(config.Channels, client.config.Channels)
 	return config
 }
 
-// SaveChannel creates or updates channel.
+// SaveChannel creates or updates channel. Creating a channel which already exists succeeds.
 func (client *Client) SaveChannel(channelID, channelConfigPath string) error {
-	return client.resourceManager.saveChannel(channelID, channelConfigPath)
+	return client.SaveChannelContext(context.Background(), channelID, channelConfigPath)
 }
 
-// JoinChannel allows for peers to join existing channel.
-func (client *Client) JoinChannel(channelID string) error {
-	if err := client.resourceManager.joinChannel(channelID); err != nil {
+// SaveChannelContext creates or updates channel. Creating a channel which already exists succeeds. The provided context
+// controls the cancellation and deadline of the request.
+func (client *Client) SaveChannelContext(ctx context.Context, channelID, channelConfigPath string) error {
+	return contextError(ctx, ignoreChannelAlreadyExists(client.resourceManager.saveChannel(ctx, channelID, channelConfigPath)))
+}
+
+// SaveChannelFromProfile creates the given channel from the profile configured for it (i.e. Channel.Profile). The channel
+// creation transaction is generated in Go, no pre-generated channel config transaction file is needed. Creating a
+// channel which already exists succeeds.
+func (client *Client) SaveChannelFromProfile(channelID string) error {
+	return client.SaveChannelFromProfileContext(context.Background(), channelID)
+}
+
+// SaveChannelFromProfileContext creates the given channel from the profile configured for it (i.e. Channel.Profile).
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) SaveChannelFromProfileContext(ctx context.Context, channelID string) error {
+	channel, ok := client.findChannel(channelID)
+	if !ok || channel.Profile == nil {
+		return fmt.Errorf("failed to save channel: no profile configured for channel '%s'", channelID)
+	}
+
+	channelTx, err := NewChannelCreationTx(channelID, channel.Profile)
+	if err != nil {
 		return err
 	}
 
+	return contextError(ctx, ignoreChannelAlreadyExists(client.resourceManager.saveChannelFromTx(ctx, channelID, channelTx)))
+}
+
+// BootstrapChannel joins the orderers configured with an admin endpoint (i.e. Config.OrdererAdmins) to the given channel,
+// using the genesis block generated from the profile configured for the channel (i.e. Channel.Profile). It is the way
+// to create a channel on networks without an ordering system channel (Fabric 2.3+). Orderers which already joined the
+// channel are skipped.
+func (client *Client) BootstrapChannel(channelID string) error {
+	return client.BootstrapChannelContext(context.Background(), channelID)
+}
+
+// BootstrapChannelContext joins the orderers configured with an admin endpoint (i.e. Config.OrdererAdmins) to the given
+// channel, using the genesis block generated from the profile configured for the channel (i.e. Channel.Profile). The
+// provided context controls the cancellation and deadline of the request.
+func (client *Client) BootstrapChannelContext(ctx context.Context, channelID string) error {
+	if len(client.ordererAdmins) == 0 {
+		return errors.New("failed to bootstrap channel: no orderer admin endpoint configured")
+	}
+
+	channel, ok := client.findChannel(channelID)
+	if !ok || channel.Profile == nil {
+		return fmt.Errorf("failed to bootstrap channel: no profile configured for channel '%s'", channelID)
+	}
+
+	genesisBlock, err := NewChannelGenesisBlock(channelID, channel.Profile)
+	if err != nil {
+		return err
+	}
+
+	for _, ordererAdmin := range client.ordererAdmins {
+		if _, err := ordererAdmin.JoinChannelContext(ctx, channelID, genesisBlock); err != nil && !errors.Is(err, ErrChannelAlreadyExists) {
+			return contextError(ctx, err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateAnchorPeers updates the anchor peers of the organization on the given channel, using the anchor peer config
+// update transaction file configured for this channel (i.e. Channel.AnchorPeerConfigPath).
+func (client *Client) UpdateAnchorPeers(channelID string) error {
+	return client.UpdateAnchorPeersContext(context.Background(), channelID)
+}
+
+// UpdateAnchorPeersContext updates the anchor peers of the organization on the given channel, using the anchor peer config
+// update transaction file configured for this channel (i.e. Channel.AnchorPeerConfigPath). The provided context controls
+// the cancellation and deadline of the request.
+func (client *Client) UpdateAnchorPeersContext(ctx context.Context, channelID string) error {
+	channel, ok := client.findChannel(channelID)
+	if !ok || channel.AnchorPeerConfigPath == "" {
+		return fmt.Errorf("failed to update anchor peers: no anchor peer config path configured for channel '%s'", channelID)
+	}
+
+	return contextError(ctx, client.resourceManager.saveChannel(ctx, channelID, channel.AnchorPeerConfigPath))
+}
+
+// UpdateAnchorPeersFromConnectionProfile sets the peers of the organization declared in the connection profile as its
+// anchor peers on the given channel. The config update is computed from the current channel configuration, no
+// pre-generated anchor peer config update transaction is needed. It is a no-op if the anchor peers are already up to date.
+func (client *Client) UpdateAnchorPeersFromConnectionProfile(channelID string) error {
+	return client.UpdateAnchorPeersFromConnectionProfileContext(context.Background(), channelID)
+}
+
+// UpdateAnchorPeersFromConnectionProfileContext sets the peers of the organization declared in the connection profile as its
+// anchor peers on the given channel. The config update is computed from the current channel configuration, no
+// pre-generated anchor peer config update transaction is needed. It is a no-op if the anchor peers are already up to date.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) UpdateAnchorPeersFromConnectionProfileContext(ctx context.Context, channelID string) error {
+	return contextError(ctx, client.resourceManager.updateAnchorPeers(ctx, channelID))
+}
+
+// UpdateChannelConfig fetches the current configuration of the given channel, lets the edit function modify it and submits
+// the resulting config update, signed by the organization admin. It is a no-op if the edit function leaves the
+// configuration unchanged.
+func (client *Client) UpdateChannelConfig(channelID string, edit func(editor *ChannelConfigEditor) error) error {
+	return client.UpdateChannelConfigContext(context.Background(), channelID, edit)
+}
+
+// UpdateChannelConfigContext fetches the current configuration of the given channel, lets the edit function modify it and
+// submits the resulting config update, signed by the organization admin. It is a no-op if the edit function leaves the
+// configuration unchanged. The provided context controls the cancellation and deadline of the request.
+func (client *Client) UpdateChannelConfigContext(ctx context.Context, channelID string, edit func(editor *ChannelConfigEditor) error) error {
+	err := client.resourceManager.updateChannelConfig(ctx, channelID, func(config *common.Config) error {
+		return edit(newChannelConfigEditor(config))
+	})
+
+	return contextError(ctx, err)
+}
+
+// CreateChannelConfigUpdate fetches the current configuration of the given channel, lets the edit function modify it and
+// returns the resulting config update envelope, unsigned. The envelope has the format of a channel config transaction
+// file, it may be written to a file and signed offline by the admin of each organization (see SignChannelConfigUpdate),
+// then merged and submitted with SubmitChannelConfigUpdate.
+func (client *Client) CreateChannelConfigUpdate(channelID string, edit func(editor *ChannelConfigEditor) error) ([]byte, error) {
+	return client.CreateChannelConfigUpdateContext(context.Background(), channelID, edit)
+}
+
+// CreateChannelConfigUpdateContext fetches the current configuration of the given channel, lets the edit function modify it
+// and returns the resulting config update envelope, unsigned. The provided context controls the cancellation and deadline
+// of the request.
+func (client *Client) CreateChannelConfigUpdateContext(ctx context.Context, channelID string, edit func(editor *ChannelConfigEditor) error) ([]byte, error) {
+	envelope, err := client.resourceManager.createChannelConfigUpdate(ctx, channelID, func(config *common.Config) error {
+		return edit(newChannelConfigEditor(config))
+	})
+
+	return envelope, contextError(ctx, err)
+}
+
+// SubmitChannelConfigUpdate submits a config update envelope along with the signatures it carries. The envelope must
+// hold enough signatures to satisfy the mod policies of the modified elements.
+func (client *Client) SubmitChannelConfigUpdate(channelID string, envelope []byte) error {
+	return client.SubmitChannelConfigUpdateContext(context.Background(), channelID, envelope)
+}
+
+// SubmitChannelConfigUpdateContext submits a config update envelope along with the signatures it carries. The provided
+// context controls the cancellation and deadline of the request.
+func (client *Client) SubmitChannelConfigUpdateContext(ctx context.Context, channelID string, envelope []byte) error {
+	return contextError(ctx, client.resourceManager.submitChannelConfigUpdate(ctx, channelID, envelope))
+}
+
+// JoinChannel allows for peers to join existing channel. Joining a channel the peers already joined succeeds.
+func (client *Client) JoinChannel(channelID string) error {
+	return client.JoinChannelContext(context.Background(), channelID)
+}
+
+// JoinChannelContext allows for peers to join existing channel. Joining a channel the peers already joined succeeds.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) JoinChannelContext(ctx context.Context, channelID string) error {
+	if err := ignoreChannelAlreadyExists(client.resourceManager.joinChannel(ctx, channelID)); err != nil {
+		return contextError(ctx, err)
+	}
+
+	return client.createChannelHandler(channelID)
+}
+
+// QueryChannels returns the channels joined by the peers of the organization.
+func (client *Client) QueryChannels() ([]string, error) {
+	return client.QueryChannelsContext(context.Background())
+}
+
+// QueryChannelsContext returns the channels joined by the peers of the organization. The provided context controls the
+// cancellation and deadline of the request.
+func (client *Client) QueryChannelsContext(ctx context.Context) ([]string, error) {
+	channels, err := client.resourceManager.queryChannels(ctx)
+	return channels, contextError(ctx, err)
+}
+
+// AttachChannel creates the handlers of a channel already joined by the peers, without joining it, so that the client
+// can interact with it (e.g. Invoke, Query, RegisterChaincodeEvent).
+func (client *Client) AttachChannel(channelID string) error {
 	return client.createChannelHandler(channelID)
 }
 
+// DetachChannel unregisters all the chaincode and block event registrations of the given channel, closing their event
+// channels, and removes its handlers. The channel can be attached again with AttachChannel or JoinChannel.
+func (client *Client) DetachChannel(channelID string) error {
+	client.mutex.Lock()
+	var detached handlers
+	for i, c := range client.channelsHandlers {
+		if c.channelName == channelID {
+			detached = c.handlers
+			client.channelsHandlers = append(client.channelsHandlers[:i], client.channelsHandlers[i+1:]...)
+			break
+		}
+	}
+	client.mutex.Unlock()
+
+	if detached == nil {
+		return fmt.Errorf("failed to detach channel '%s': %w", channelID, ErrChannelNotFound)
+	}
+
+	detached.close()
+	return nil
+}
+
+// AddUser adds a user to the client and creates its handlers for every attached channel, the user can then be selected
+// with WithUserContext. The certificate and private key of the user are read from their paths.
+func (client *Client) AddUser(user Identity) error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.closed {
+		return fmt.Errorf("failed to add user '%s': client is closed", user.Username)
+	}
+
+	if len(user.Username) == 0 {
+		return errors.New("failed to add user: username is empty")
+	}
+
+	if client.findUser(user.Username) >= 0 {
+		return fmt.Errorf("failed to add user: user '%s' already exists", user.Username)
+	}
+
+	userHandlers, err := client.createUserHandlers(user)
+	if err != nil {
+		return fmt.Errorf("failed to add user '%s': %w", user.Username, err)
+	}
+
+	for i := range client.channelsHandlers {
+		client.channelsHandlers[i].handlers = append(client.channelsHandlers[i].handlers, handler{
+			username: user.Username,
+			handler:  userHandlers[i],
+		})
+	}
+
+	// the users are copied so that the slice of the config given to NewClient is left untouched
+	users := make([]Identity, len(client.config.Identities.Users), len(client.config.Identities.Users)+1)
+	copy(users, client.config.Identities.Users)
+	client.config.Identities.Users = append(users, user)
+	return nil
+}
+
+// RemoveUser removes a user from the client along with its handlers, the event registrations made on behalf of the user
+// are unregistered. Requests in progress on behalf of the user are not interrupted. The last user cannot be removed.
+func (client *Client) RemoveUser(username string) error {
+	client.mutex.Lock()
+
+	if client.closed {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to remove user '%s': client is closed", username)
+	}
+
+	index := client.findUser(username)
+	if index < 0 {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to remove user '%s': %w", username, ErrUserNotFound)
+	}
+
+	if len(client.config.Identities.Users) == 1 {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to remove user: '%s' is the last user of the client", username)
+	}
+
+	removed := make(handlers, 0, len(client.channelsHandlers))
+	for i, c := range client.channelsHandlers {
+		remaining := make(handlers, 0, len(c.handlers))
+		for _, h := range c.handlers {
+			if h.username == username {
+				removed = append(removed, h)
+				continue
+			}
+
+			remaining = append(remaining, h)
+		}
+
+		client.channelsHandlers[i].handlers = remaining
+	}
+
+	users := client.config.Identities.Users
+	client.config.Identities.Users = append(users[:index:index], users[index+1:]...)
+	client.mutex.Unlock()
+
+	removed.close()
+	return nil
+}
+
+// RotateUser replaces the certificate and private key of a user, read from their paths, and atomically replaces its
+// handlers for every attached channel. Requests in progress complete with the previous identity. The event
+// registrations made with the previous identity are unregistered, they must be registered again.
+func (client *Client) RotateUser(username, certificate, privateKey string) error {
+	client.mutex.Lock()
+
+	if client.closed {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to rotate user '%s': client is closed", username)
+	}
+
+	index := client.findUser(username)
+	if index < 0 {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to rotate user '%s': %w", username, ErrUserNotFound)
+	}
+
+	user := Identity{
+		Certificate: certificate,
+		PrivateKey:  privateKey,
+		Username:    username,
+	}
+
+	userHandlers, err := client.createUserHandlers(user)
+	if err != nil {
+		client.mutex.Unlock()
+		return fmt.Errorf("failed to rotate user '%s': %w", username, err)
+	}
+
+	replaced := make(handlers, 0, len(client.channelsHandlers))
+	for i, c := range client.channelsHandlers {
+		rotated := make(handlers, len(c.handlers))
+		copy(rotated, c.handlers)
+
+		for j := range rotated {
+			if rotated[j].username == username {
+				replaced = append(replaced, rotated[j])
+				rotated[j].handler = userHandlers[i]
+			}
+		}
+
+		client.channelsHandlers[i].handlers = rotated
+	}
+
+	users := make([]Identity, len(client.config.Identities.Users))
+	copy(users, client.config.Identities.Users)
+	users[index] = user
+	client.config.Identities.Users = users
+	client.mutex.Unlock()
+
+	replaced.close()
+	return nil
+}
+
 // LifecycleInstallChaincode installs a chaincode package using Fabric 2.0 chaincode lifecycle. Returns the chaincode package ID if the install succeeded.
 func (client *Client) LifecycleInstallChaincode(chaincode Chaincode) (string, error) {
-	return client.resourceManager.lifecycleInstallChaincode(chaincode)
+	return client.LifecycleInstallChaincodeContext(context.Background(), chaincode)
+}
+
+// LifecycleInstallChaincodeContext installs a chaincode package using Fabric 2.0 chaincode lifecycle. Returns the chaincode package ID if the install succeeded.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) LifecycleInstallChaincodeContext(ctx context.Context, chaincode Chaincode) (string, error) {
+	packageID, err := client.resourceManager.lifecycleInstallChaincode(ctx, chaincode)
+	return packageID, contextError(ctx, err)
 }
 
 // LifecycleApproveChaincode approves a chaincode for an organization.
 func (client *Client) LifecycleApproveChaincode(channelID, packageID string, chaincode Chaincode) error {
-	return client.resourceManager.lifecycleApproveChaincode(channelID, packageID, chaincode)
+	return client.LifecycleApproveChaincodeContext(context.Background(), channelID, packageID, chaincode)
+}
+
+// LifecycleApproveChaincodeContext approves a chaincode for an organization. The provided context controls the cancellation and deadline of the request.
+func (client *Client) LifecycleApproveChaincodeContext(ctx context.Context, channelID, packageID string, chaincode Chaincode) error {
+	return contextError(ctx, client.resourceManager.lifecycleApproveChaincode(ctx, channelID, packageID, chaincode))
 }
 
 // LifecyleCheckChaincodeCommitReadiness checks the 'commit readiness' of a chaincode. Returns a map holding the org approvals.
 func (client *Client) LifecyleCheckChaincodeCommitReadiness(channelID string, chaincode Chaincode) (map[string]bool, error) {
-	return client.resourceManager.lifecycleCheckChaincodeCommitReadiness(channelID, chaincode)
+	return client.LifecyleCheckChaincodeCommitReadinessContext(context.Background(), channelID, chaincode)
+}
+
+// LifecyleCheckChaincodeCommitReadinessContext checks the 'commit readiness' of a chaincode. Returns a map holding the org approvals.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) LifecyleCheckChaincodeCommitReadinessContext(ctx context.Context, channelID string, chaincode Chaincode) (map[string]bool, error) {
+	approvals, err := client.resourceManager.lifecycleCheckChaincodeCommitReadiness(ctx, channelID, chaincode)
+	return approvals, contextError(ctx, err)
 }
 
 // LifecycleCommitChaincode commits the chaincode to the given channel.
 func (client *Client) LifecycleCommitChaincode(channelID string, chaincode Chaincode) error {
-	return client.resourceManager.lifecycleCommitChaincode(channelID, chaincode)
+	return client.LifecycleCommitChaincodeContext(context.Background(), channelID, chaincode)
+}
+
+// LifecycleCommitChaincodeContext commits the chaincode to the given channel. The provided context controls the cancellation and deadline of the request.
+func (client *Client) LifecycleCommitChaincodeContext(ctx context.Context, channelID string, chaincode Chaincode) error {
+	return contextError(ctx, client.resourceManager.lifecycleCommitChaincode(ctx, channelID, chaincode))
 }
 
 // IsChaincodeInstalled returns whether the given chaincode has been installed or not.
 func (client *Client) IsChaincodeInstalled(packageID string) bool {
-	return client.resourceManager.isChaincodeInstalled(packageID)
+	return client.IsChaincodeInstalledContext(context.Background(), packageID)
+}
+
+// IsChaincodeInstalledContext returns whether the given chaincode has been installed or not. It returns false if the context is done before
+// all peers answered.
+func (client *Client) IsChaincodeInstalledContext(ctx context.Context, packageID string) bool {
+	return client.resourceManager.isChaincodeInstalled(ctx, packageID)
 }
 
 // IsChaincodeApproved returns whether the given chaincode has been approved or not.
 func (client *Client) IsChaincodeApproved(channelID, chaincodeName string, sequence int64) bool {
-	return client.resourceManager.isChaincodeApproved(channelID, chaincodeName, sequence)
+	return client.IsChaincodeApprovedContext(context.Background(), channelID, chaincodeName, sequence)
+}
+
+// IsChaincodeApprovedContext returns whether the given chaincode has been approved or not. It returns false if the context is done before
+// all peers answered.
+func (client *Client) IsChaincodeApprovedContext(ctx context.Context, channelID, chaincodeName string, sequence int64) bool {
+	return client.resourceManager.isChaincodeApproved(ctx, channelID, chaincodeName, sequence)
 }
 
 // IsChaincodeCommitted returns whether the given chaincode has been committed or not.
 func (client *Client) IsChaincodeCommitted(channelID, chaincodeName string, sequence int64) bool {
-	return client.resourceManager.isChaincodeCommitted(channelID, chaincodeName, sequence)
+	return client.IsChaincodeCommittedContext(context.Background(), channelID, chaincodeName, sequence)
+}
+
+// IsChaincodeCommittedContext returns whether the given chaincode has been committed or not. It returns false if the context is done before
+// all peers answered.
+func (client *Client) IsChaincodeCommittedContext(ctx context.Context, channelID, chaincodeName string, sequence int64) bool {
+	return client.resourceManager.isChaincodeCommitted(ctx, channelID, chaincodeName, sequence)
 }
 
 // Invoke prepares and executes transaction using request and optional request options.
 func (client *Client) Invoke(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
+	return client.InvokeContext(context.Background(), request, opts...)
+}
+
+// InvokeContext prepares and executes transaction using request and optional request options.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) InvokeContext(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := handler.invoke(request, opts...)
+	response, err := handler.invoke(ctx, request, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to invoke chaincode '%s': %w", request.ChaincodeID, err)
+		return nil, fmt.Errorf("failed to invoke chaincode '%s': %w", request.ChaincodeID, contextError(ctx, err))
 	}
 
 	return response, nil
 }
 
+// Endorse prepares the transaction using request and optional request options, and collects its endorsements without
+// submitting it. The endorsed transaction can be inspected, persisted or discarded before being submitted with Submit.
+func (client *Client) Endorse(request *ChaincodeRequest, opts ...Option) (*EndorsedTransaction, error) {
+	return client.EndorseContext(context.Background(), request, opts...)
+}
+
+// EndorseContext prepares the transaction using request and optional request options, and collects its endorsements
+// without submitting it. The provided context controls the cancellation and deadline of the request.
+func (client *Client) EndorseContext(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*EndorsedTransaction, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := handler.endorse(ctx, request, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to endorse transaction of chaincode '%s': %w", request.ChaincodeID, contextError(ctx, err))
+	}
+
+	return tx, nil
+}
+
+// Submit sends a transaction endorsed by Endorse to the orderer and waits for it to be committed. The transaction is
+// submitted on the channel it has been endorsed on, by the user it has been endorsed for (see WithUserContext).
+// The transaction is never retried, whatever the retry policy: it must be endorsed again to be retried.
+func (client *Client) Submit(tx *EndorsedTransaction, opts ...Option) (*TransactionResponse, error) {
+	return client.SubmitContext(context.Background(), tx, opts...)
+}
+
+// SubmitContext sends a transaction endorsed by Endorse to the orderer and waits for it to be committed. The transaction
+// is submitted on the channel it has been endorsed on, by the user it has been endorsed for (see WithUserContext).
+// The transaction is never retried, whatever the retry policy: it must be endorsed again to be retried.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) SubmitContext(ctx context.Context, tx *EndorsedTransaction, opts ...Option) (*TransactionResponse, error) {
+	if tx == nil {
+		return nil, errors.New("failed to submit transaction: no endorsed transaction given")
+	}
+
+	handler, err := client.selectChannelHandler(append([]Option{WithChannelContext(tx.ChannelID)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := handler.submit(ctx, tx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction '%s': %w", tx.TransactionID, contextError(ctx, err))
+	}
+
+	return response, nil
+}
+
+// SubmitAsync prepares the transaction using request and optional request options, and sends it to the orderer without
+// waiting for it to be committed. The returned commit is resolved once the commit event of the transaction has been
+// received (see WithCommitTimeout).
+func (client *Client) SubmitAsync(request *ChaincodeRequest, opts ...Option) (*Commit, error) {
+	return client.SubmitAsyncContext(context.Background(), request, opts...)
+}
+
+// SubmitAsyncContext prepares the transaction using request and optional request options, and sends it to the orderer
+// without waiting for it to be committed. The provided context controls the cancellation and deadline of the endorsement
+// and the submission of the transaction, Commit.Wait controls how long the commit is awaited.
+func (client *Client) SubmitAsyncContext(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*Commit, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := handler.submitAsync(ctx, request, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit transaction to chaincode '%s': %w", request.ChaincodeID, contextError(ctx, err))
+	}
+
+	return commit, nil
+}
+
 // Query chaincode using request and optional request options.
 func (client *Client) Query(request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
+	return client.QueryContext(context.Background(), request, opts...)
+}
+
+// QueryContext queries chaincode using request and optional request options.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryContext(ctx context.Context, request *ChaincodeRequest, opts ...Option) (*TransactionResponse, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := handler.query(request, opts...)
+	response, err := handler.query(ctx, request, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query chaincode '%s': %w", request.ChaincodeID, err)
+		return nil, fmt.Errorf("failed to query chaincode '%s': %w", request.ChaincodeID, contextError(ctx, err))
 	}
 
 	return response, nil
@@ -249,14 +824,19 @@ func (client *Client) Query(request *ChaincodeRequest, opts ...Option) (*Transac
 
 // QueryBlock queries the ledger for Block by block number.
 func (client *Client) QueryBlock(blockNumber uint64, opts ...Option) (*Block, error) {
+	return client.QueryBlockContext(context.Background(), blockNumber, opts...)
+}
+
+// QueryBlockContext queries the ledger for Block by block number. The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryBlockContext(ctx context.Context, blockNumber uint64, opts ...Option) (*Block, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	block, err := handler.queryBlock(blockNumber)
+	block, err := handler.queryBlock(ctx, blockNumber, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query the ledger to retrieve the block number '%d': %w", blockNumber, err)
+		return nil, fmt.Errorf("failed to query the ledger to retrieve the block number '%d': %w", blockNumber, contextError(ctx, err))
 	}
 
 	return block, nil
@@ -264,14 +844,19 @@ func (client *Client) QueryBlock(blockNumber uint64, opts ...Option) (*Block, er
 
 // QueryBlockByHash queries the ledger for block by block hash.
 func (client *Client) QueryBlockByHash(blockHash []byte, opts ...Option) (*Block, error) {
+	return client.QueryBlockByHashContext(context.Background(), blockHash, opts...)
+}
+
+// QueryBlockByHashContext queries the ledger for block by block hash. The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryBlockByHashContext(ctx context.Context, blockHash []byte, opts ...Option) (*Block, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	block, err := handler.queryBlockByHash(blockHash)
+	block, err := handler.queryBlockByHash(ctx, blockHash, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query the ledger to retrieve a block by block hash (%v): %w", blockHash, err)
+		return nil, fmt.Errorf("failed to query the ledger to retrieve a block by block hash (%v): %w", blockHash, contextError(ctx, err))
 	}
 
 	return block, nil
@@ -279,53 +864,171 @@ func (client *Client) QueryBlockByHash(blockHash []byte, opts ...Option) (*Block
 
 // QueryBlockByTxID queries for block which contains a transaction.
 func (client *Client) QueryBlockByTxID(txID string, opts ...Option) (*Block, error) {
+	return client.QueryBlockByTxIDContext(context.Background(), txID, opts...)
+}
+
+// QueryBlockByTxIDContext queries for block which contains a transaction. The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryBlockByTxIDContext(ctx context.Context, txID string, opts ...Option) (*Block, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	block, err := handler.queryBlockByTxID(txID)
+	block, err := handler.queryBlockByTxID(ctx, txID, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query the ledger to retrieve the block which contains the transaction ID '%s': %w", txID, err)
+		return nil, fmt.Errorf("failed to query the ledger to retrieve the block which contains the transaction ID '%s': %w", txID, contextError(ctx, err))
 	}
 
 	return block, nil
 }
 
+// QueryChannelConfig queries for the configuration of the channel, as recorded in its last configuration block.
+func (client *Client) QueryChannelConfig(opts ...Option) (*ChannelConfig, error) {
+	return client.QueryChannelConfigContext(context.Background(), opts...)
+}
+
+// QueryChannelConfigContext queries for the configuration of the channel, as recorded in its last configuration block.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryChannelConfigContext(ctx context.Context, opts ...Option) (*ChannelConfig, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	channelConfig, err := handler.queryChannelConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the channel configuration: %w", contextError(ctx, err))
+	}
+
+	return channelConfig, nil
+}
+
 // QueryInfo queries for various useful blockchain information on this channel such as block height and current block hash.
 func (client *Client) QueryInfo(opts ...Option) (*BlockchainInfo, error) {
+	return client.QueryInfoContext(context.Background(), opts...)
+}
+
+// QueryInfoContext queries for various useful blockchain information on this channel such as block height and current block hash.
+// The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryInfoContext(ctx context.Context, opts ...Option) (*BlockchainInfo, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	blockchainInfo, err := handler.queryInfo()
+	blockchainInfo, err := handler.queryInfo(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query blockchain information: %w", err)
+		return nil, fmt.Errorf("failed to query blockchain information: %w", contextError(ctx, err))
 	}
 
 	return blockchainInfo, nil
 }
 
+// QueryTransaction queries for a committed transaction. It returns the decoded transaction along with its validation code
+// and the number of the block which contains it.
+func (client *Client) QueryTransaction(txID string, opts ...Option) (*ProcessedTransaction, error) {
+	return client.QueryTransactionContext(context.Background(), txID, opts...)
+}
+
+// QueryTransactionContext queries for a committed transaction. It returns the decoded transaction along with its validation code
+// and the number of the block which contains it. The provided context controls the cancellation and deadline of the request.
+func (client *Client) QueryTransactionContext(ctx context.Context, txID string, opts ...Option) (*ProcessedTransaction, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	processedTransaction, err := handler.queryTransaction(ctx, txID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the ledger to retrieve the transaction '%s': %w", txID, contextError(ctx, err))
+	}
+
+	return processedTransaction, nil
+}
+
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
+// By default, only the events of the blocks committed after the registration are delivered. Use WithStartBlock, WithSeekOldest
+// or WithSeekNewest to replay events from a known position. The event channel is closed once the registration is over.
 func (client *Client) RegisterChaincodeEvent(chaincodeID, eventFilter string, opts ...Option) (<-chan *ChaincodeEvent, error) {
+	events, _, err := client.RegisterChaincodeEventWithRegistration(chaincodeID, eventFilter, opts...)
+	return events, err
+}
+
+// RegisterChaincodeEventWithRegistration registers for chaincode events as RegisterChaincodeEvent does, but also returns
+// the registration. Its Err method tells whether the event channel has been closed because of an error.
+func (client *Client) RegisterChaincodeEventWithRegistration(chaincodeID, eventFilter string, opts ...Option) (<-chan *ChaincodeEvent, *EventRegistration, error) {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return handler.registerChaincodeEvent(chaincodeID, eventFilter)
+	return handler.registerChaincodeEvent(chaincodeID, eventFilter, opts...)
 }
 
-// UnregisterChaincodeEvent removes the given registration and closes the event channel.
+// UnregisterChaincodeEvent removes the registrations of the given event filter and closes their event channel.
+// Only the registrations of the subscriber given with WithSubscriber are removed, whatever their chaincode.
 func (client *Client) UnregisterChaincodeEvent(eventFilter string, opts ...Option) error {
 	handler, err := client.selectChannelHandler(opts...)
 	if err != nil {
 		return err
 	}
-	handler.unregisterChaincodeEvent(eventFilter)
+	handler.unregisterChaincodeEvent(eventFilter, opts...)
 	return nil
 }
 
+// RegisterBlockEvent registers for block events. The returned registration must be unregistered when it is no longer needed.
+// Use WithStartBlock, WithSeekOldest or WithSeekNewest to replay blocks from a known position.
+func (client *Client) RegisterBlockEvent(opts ...Option) (<-chan *Block, *EventRegistration, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return handler.registerBlockEvent(opts...)
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events. The returned registration must be unregistered when it is no longer needed.
+// Use WithStartBlock, WithSeekOldest or WithSeekNewest to replay blocks from a known position.
+func (client *Client) RegisterFilteredBlockEvent(opts ...Option) (<-chan *FilteredBlock, *EventRegistration, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return handler.registerFilteredBlockEvent(opts...)
+}
+
+// RegisterTxStatusEvent registers for the status event of the given transaction. The event is delivered once the transaction
+// has been committed, it holds the final validation code of the transaction as well as the number of the block containing it.
+// The returned registration must be unregistered when it is no longer needed. Use WithStartBlock to confirm a transaction
+// that may have been committed before the registration.
+func (client *Client) RegisterTxStatusEvent(txID string, opts ...Option) (<-chan *TxStatusEvent, *EventRegistration, error) {
+	handler, err := client.selectChannelHandler(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return handler.registerTxStatusEvent(txID, opts...)
+}
+
+// findUser returns the index of the given user in the configuration, -1 if not found. The caller must hold the lock of
+// the client.
+func (client *Client) findUser(username string) int {
+	for i, user := range client.config.Identities.Users {
+		if user.Username == username {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (client *Client) findChannel(channelID string) (Channel, bool) {
+	for _, channel := range client.config.Channels {
+		if channel.Name == channelID {
+			return channel, true
+		}
+	}
+
+	return Channel{}, false
+}
+
 func (client *Client) selectChannelHandler(opts ...Option) (channelHandler, error) {
 	client.mutex.RLock()
 	defer client.mutex.RUnlock()
@@ -339,18 +1042,26 @@ func (client *Client) selectChannelHandler(opts ...Option) (channelHandler, erro
 		opt.apply(options)
 	}
 
+	if len(options.channelID) == 0 && len(client.channelsHandlers) == 0 {
+		return nil, fmt.Errorf("no channel attached: %w", ErrChannelNotFound)
+	}
+
 	if len(options.channelID) == 0 && len(client.channelsHandlers) > 1 {
-		return nil, errors.New("cannot determine channel context")
+		return nil, ErrAmbiguousChannel
 	}
 
 	if len(options.channelID) == 0 {
 		if len(options.userIdentity) == 0 {
+			if len(client.channelsHandlers[0].handlers) == 0 {
+				return nil, fmt.Errorf("no channel handler found: %w", ErrUserNotFound)
+			}
+
 			return client.channelsHandlers[0].handlers[0].handler, nil
 		}
 
 		handler := client.channelsHandlers[0].handlers.find(options.userIdentity)
 		if handler == nil {
-			return nil, fmt.Errorf("no channel handler found for user context '%s'", options.userIdentity)
+			return nil, fmt.Errorf("no channel handler found for user context '%s': %w", options.userIdentity, ErrUserNotFound)
 		}
 
 		return handler, nil
@@ -358,17 +1069,21 @@ func (client *Client) selectChannelHandler(opts ...Option) (channelHandler, erro
 
 	chanHandlers := client.channelsHandlers.find(options.channelID)
 	if chanHandlers == nil {
-		return nil, fmt.Errorf("handler for channel '%s' not found", options.channelID)
+		return nil, fmt.Errorf("handler for channel '%s' not found: %w", options.channelID, ErrChannelNotFound)
 	}
 
 	if len(options.userIdentity) > 0 {
 		handler := chanHandlers.find(options.userIdentity)
 		if handler == nil {
-			return nil, fmt.Errorf("no channel handler found for user context '%s'", options.userIdentity)
+			return nil, fmt.Errorf("no channel handler found for user context '%s': %w", options.userIdentity, ErrUserNotFound)
 		}
 
 		return handler, nil
 	}
 
+	if len(chanHandlers) == 0 {
+		return nil, fmt.Errorf("no channel handler found for channel '%s': %w", options.channelID, ErrUserNotFound)
+	}
+
 	return chanHandlers[0].handler, nil
 }
@@ -1,6 +1,7 @@
 package fabclient
 
 import (
+	"errors"
 	"os"
 	"testing"
 )
@@ -64,12 +65,12 @@ func TestCreateUpdateJoinChannelForOrg1AndOrg2(t *testing.T) {
 	}
 
 	handler, err = org1client.selectChannelHandler(WithChannelContext("dummy"))
-	if err == nil || handler != nil {
+	if !errors.Is(err, ErrChannelNotFound) || handler != nil {
 		t.Error("should have returned an error when selecting channel handler for channel: dummy")
 	}
 
 	handler, err = org1client.selectChannelHandler(WithUserContext("foo"))
-	if err == nil || handler != nil {
+	if !errors.Is(err, ErrUserNotFound) || handler != nil {
 		t.Error("should have returned an error when selecting channel handler for user: foo")
 	}
 
@@ -79,6 +80,19 @@ func TestCreateUpdateJoinChannelForOrg1AndOrg2(t *testing.T) {
 	}
 }
 
+func TestQueryAndAttachChannels(t *testing.T) {
+	queryAndAttachChannels(t, org1client)
+}
+
+func TestManageUsers(t *testing.T) {
+	manageUsers(t, org1client)
+}
+
+func TestUpdateChannelConfig(t *testing.T) {
+	updateChannelConfig(t, org1client)
+	multiPartyChannelConfigUpdate(t, org1client, org2client)
+}
+
 func TestChannelManagementFailureCases(t *testing.T) {
 	channelManagementFailureCases(t, org1client)
 }
@@ -102,14 +116,27 @@ func TestChaincodeOperations(t *testing.T) {
 	readFromLedger(t, org2client)
 	queryBlock(t, org1client)
 	queryBlockByTxID(t, org2client)
+	queryTransaction(t, org2client)
+	queryChannelConfig(t, org1client)
 	queryInfo(t, org1client)
 	queryBlockByHash(t, org2client)
 	registerChaincodeEvent(t, org1client)
 	chaincodeEventTimeout(t, org1client)
+	registerBlockEvent(t, org1client)
+	replayEvents(t, org1client)
+	checkpointedChaincodeEvent(t, org1client)
+	multipleChaincodeEventSubscribers(t, org1client)
+	submitAsync(t, org1client)
+	endorseAndSubmit(t, org1client)
+	detachChannel(t, org1client)
 	chaincodePrivateDataCollection(t, org1client, org2client)
 	chaincodeOpsFailureCases(t, org1client)
+	chaincodeOpsWithCanceledContext(t, org1client)
 	testConvertBlockchainInfo(t)
 	testConvertChaincodeRequest(t)
+	testConvertChaincodeTransactionResponse(t)
+	testConvertFilteredBlock(t)
+	testConvertTxStatusEvent(t)
 }
 
 func TestGatewayWrapping(t *testing.T) {
@@ -121,6 +148,34 @@ func TestGatewayWrapping(t *testing.T) {
 func TestCloseClient(t *testing.T) {
 	org1client.Close()
 	org2client.Close()
+	org1client.Close()
+
+	user := org1client.Config().Identities.Users[0]
+	if err := org1client.AddUser(Identity{Username: "closed", Certificate: user.Certificate, PrivateKey: user.PrivateKey}); err == nil {
+		t.Error("should have returned an error, client is closed")
+	}
+
+	if err := org1client.RotateUser(user.Username, user.Certificate, user.PrivateKey); err == nil {
+		t.Error("should have returned an error, client is closed")
+	}
+}
+
+func TestSelectChannelHandler(t *testing.T) {
+	client := &Client{
+		channelsHandlers: channelsHandlers{{channelName: "channelall", handlers: handlers{}}},
+	}
+
+	if _, err := client.selectChannelHandler(); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("should have returned ErrUserNotFound, channel has no handler, but got: %v", err)
+	}
+
+	if _, err := client.selectChannelHandler(WithChannelContext("channelall")); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("should have returned ErrUserNotFound, channel has no handler, but got: %v", err)
+	}
+
+	if err := client.AddUser(Identity{}); err == nil {
+		t.Error("should have returned an error, username is empty")
+	}
 }
 
 func TestMain(m *testing.M) {
@@ -0,0 +1,102 @@
+package fabclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+)
+
+// Commit is the handle of a transaction submitted to the orderer by SubmitAsync. It is resolved once the commit event
+// of the transaction has been received, or once the commit timeout expired.
+type Commit struct {
+	txID string
+	done chan struct{}
+
+	err      error
+	txStatus *TxStatusEvent
+	mutex    sync.RWMutex
+}
+
+func newCommit(txID string) *Commit {
+	return &Commit{
+		txID: txID,
+		done: make(chan struct{}),
+	}
+}
+
+// TxID returns the ID of the transaction.
+func (c *Commit) TxID() string {
+	return c.txID
+}
+
+// Done returns a channel which is closed once the commit is resolved.
+func (c *Commit) Done() <-chan struct{} {
+	return c.done
+}
+
+// Status returns the status of the transaction once committed, nil while the commit event has not been received.
+func (c *Commit) Status() *TxStatusEvent {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.txStatus
+}
+
+// Wait waits for the commit to be resolved and returns the status of the transaction. A TransactionValidationError is
+// returned when the transaction has been invalidated, ErrCommitStatusUnknown when its status could not be received.
+// The provided context only controls how long the caller waits, the commit keeps being awaited once it is done.
+func (c *Commit) Wait(ctx context.Context) (*TxStatusEvent, error) {
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.txStatus, c.err
+}
+
+// resolve waits for the status of the transaction to be delivered by the registration, at most for the given timeout.
+func (c *Commit) resolve(statuses <-chan *TxStatusEvent, registration *EventRegistration, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var (
+		err      error
+		txStatus *TxStatusEvent
+	)
+
+	select {
+	case event, ok := <-statuses:
+		switch {
+		case !ok && registration.Err() != nil:
+			err = fmt.Errorf("%w: %v", ErrCommitStatusUnknown, registration.Err())
+		case !ok:
+			err = fmt.Errorf("%w: registration closed before receiving block event", ErrCommitStatusUnknown)
+		case event.TxValidationCode != TxValidationCodeValid:
+			txStatus = event
+			err = &TransactionValidationError{
+				TransactionID: c.txID,
+				Code:          event.TxValidationCode,
+				err:           status.New(status.EventServerStatus, int32(event.TxValidationCode), "received invalid transaction", nil),
+			}
+		default:
+			txStatus = event
+		}
+	case <-timer.C:
+		registration.Unregister()
+		err = fmt.Errorf("%w: commit timeout expired before receiving block event", ErrCommitStatusUnknown)
+	}
+
+	c.mutex.Lock()
+	c.err = err
+	c.txStatus = txStatus
+	c.mutex.Unlock()
+
+	close(c.done)
+}
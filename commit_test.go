@@ -0,0 +1,83 @@
+package fabclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+func TestCommit(t *testing.T) {
+	statuses := make(chan *TxStatusEvent, 1)
+	registration := newEventRegistration(func() {})
+
+	commit := newCommit("txID")
+	if commit.TxID() != "txID" || commit.Status() != nil {
+		t.Errorf("unexpected pending commit: %+v", commit)
+	}
+
+	go commit.resolve(statuses, registration, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := commit.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned the error of the context: %v", err)
+	}
+
+	statuses <- &TxStatusEvent{TxID: "txID", TxValidationCode: TxValidationCodeValid, BlockNumber: 42}
+
+	txStatus, err := commit.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if txStatus.BlockNumber != 42 || commit.Status() != txStatus {
+		t.Errorf("unexpected transaction status: %+v", txStatus)
+	}
+
+	select {
+	case <-commit.Done():
+	default:
+		t.Error("commit should be resolved")
+	}
+
+	commit = newCommit("txID")
+	statuses <- &TxStatusEvent{TxID: "txID", TxValidationCode: TxValidationCode(peer.TxValidationCode_MVCC_READ_CONFLICT)}
+	go commit.resolve(statuses, registration, time.Minute)
+
+	var validationError *TransactionValidationError
+	if _, err := commit.Wait(context.Background()); !errors.As(err, &validationError) || !IsRetryable(err) {
+		t.Errorf("should have returned a retryable transaction validation error: %v", err)
+	}
+
+	if commit.Status() == nil || commit.Status().TxValidationCode.String() != "MVCC_READ_CONFLICT" {
+		t.Errorf("unexpected transaction status: %+v", commit.Status())
+	}
+
+	commit = newCommit("txID")
+	close(statuses)
+	go commit.resolve(statuses, registration, time.Minute)
+
+	if _, err := commit.Wait(context.Background()); !errors.Is(err, ErrCommitStatusUnknown) {
+		t.Errorf("should have returned an unknown commit status error: %v", err)
+	}
+
+	unregistered := make(chan struct{})
+	registration = newEventRegistration(func() { close(unregistered) })
+
+	commit = newCommit("txID")
+	go commit.resolve(make(chan *TxStatusEvent), registration, time.Millisecond)
+
+	if _, err := commit.Wait(context.Background()); !errors.Is(err, ErrCommitStatusUnknown) || IsRetryable(err) {
+		t.Errorf("should have returned a non retryable unknown commit status error on timeout: %v", err)
+	}
+
+	select {
+	case <-unregistered:
+	case <-time.After(time.Second):
+		t.Error("registration should have been unregistered on timeout")
+	}
+}
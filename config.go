@@ -20,7 +20,8 @@ type Config struct {
 		Admin Identity   `json:"admin" yaml:"admin"`
 		Users []Identity `json:"users" yaml:"users"`
 	} `json:"identities" yaml:"identities"`
-	Organization string `json:"organization" yaml:"organization"`
+	OrdererAdmins []OrdererAdmin `json:"ordererAdmins,omitempty" yaml:"ordererAdmins,omitempty"`
+	Organization  string         `json:"organization" yaml:"organization"`
 }
 
 // NewConfigFromFile returns a new client configuration.
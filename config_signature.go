@@ -0,0 +1,218 @@
+package fabclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+const nonceSize = 24
+
+// SignChannelConfigUpdate adds the signature of the given identity, member of the organization identified by the MSP
+// ID, to a config update envelope created with Client.CreateChannelConfigUpdate. The identity certificate and private
+// key are read from their paths, no connection to the network is needed. Only ECDSA keys are supported.
+func SignChannelConfigUpdate(envelope []byte, mspID string, identity Identity) ([]byte, error) {
+	configUpdateEnvelope, err := decodeConfigUpdateEnvelope(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	certificate, privateKey, err := loadIdentity(identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: certificate})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	signatureHeader, err := proto.Marshal(&common.SignatureHeader{Creator: creator, Nonce: nonce})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	digest := sha256.Sum256(append(append([]byte{}, signatureHeader...), configUpdateEnvelope.ConfigUpdate...))
+
+	signature, err := signECDSA(privateKey, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign config update: %w", err)
+	}
+
+	configUpdateEnvelope.Signatures = append(configUpdateEnvelope.Signatures, &common.ConfigSignature{
+		SignatureHeader: signatureHeader,
+		Signature:       signature,
+	})
+
+	return encodeConfigUpdateEnvelope(envelope, configUpdateEnvelope)
+}
+
+// MergeChannelConfigUpdateSignatures merges the signatures of several copies of the same config update envelope, each
+// of them signed by a different organization, into a single envelope ready to be submitted.
+func MergeChannelConfigUpdateSignatures(envelopes ...[]byte) ([]byte, error) {
+	if len(envelopes) == 0 {
+		return nil, errors.New("failed to merge config update signatures: no config update provided")
+	}
+
+	merged, err := decodeConfigUpdateEnvelope(envelopes[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config update signatures: %w", err)
+	}
+
+	signatures := merged.Signatures
+	merged.Signatures = nil
+
+	for _, envelope := range envelopes[1:] {
+		configUpdateEnvelope, err := decodeConfigUpdateEnvelope(envelope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge config update signatures: %w", err)
+		}
+
+		if !bytes.Equal(configUpdateEnvelope.ConfigUpdate, merged.ConfigUpdate) {
+			return nil, errors.New("failed to merge config update signatures: config updates differ")
+		}
+
+		signatures = append(signatures, configUpdateEnvelope.Signatures...)
+	}
+
+	for _, signature := range signatures {
+		if !containsConfigSignature(merged.Signatures, signature) {
+			merged.Signatures = append(merged.Signatures, signature)
+		}
+	}
+
+	return encodeConfigUpdateEnvelope(envelopes[0], merged)
+}
+
+func containsConfigSignature(signatures []*common.ConfigSignature, signature *common.ConfigSignature) bool {
+	for _, s := range signatures {
+		if bytes.Equal(s.Signature, signature.Signature) && bytes.Equal(s.SignatureHeader, signature.SignatureHeader) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeConfigUpdateEnvelope(envelopeBytes []byte) (*common.ConfigUpdateEnvelope, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(envelopeBytes, envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal envelope: %w", err)
+	}
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.GetHeader().GetChannelHeader(), channelHeader); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal channel header: %w", err)
+	}
+
+	if channelHeader.Type != int32(common.HeaderType_CONFIG_UPDATE) {
+		return nil, fmt.Errorf("envelope is of type %s, expected CONFIG_UPDATE", common.HeaderType(channelHeader.Type))
+	}
+
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configUpdateEnvelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config update envelope: %w", err)
+	}
+
+	return configUpdateEnvelope, nil
+}
+
+// encodeConfigUpdateEnvelope replaces the config update envelope carried by the given envelope.
+func encodeConfigUpdateEnvelope(envelopeBytes []byte, configUpdateEnvelope *common.ConfigUpdateEnvelope) ([]byte, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(envelopeBytes, envelope); err != nil {
+		return nil, err
+	}
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if payload.Data, err = proto.Marshal(configUpdateEnvelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Payload, err = proto.Marshal(payload); err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(envelope)
+}
+
+func loadIdentity(identity Identity) ([]byte, *ecdsa.PrivateKey, error) {
+	certificate, err := ioutil.ReadFile(identity.Certificate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if block, _ := pem.Decode(certificate); block == nil {
+		return nil, nil, fmt.Errorf("invalid certificate '%s': no PEM data found", identity.Certificate)
+	} else if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return nil, nil, fmt.Errorf("invalid certificate '%s': %w", identity.Certificate, err)
+	}
+
+	privateKeyPEM, err := ioutil.ReadFile(identity.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("invalid private key '%s': no PEM data found", identity.PrivateKey)
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return certificate, key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid private key '%s': %w", identity.PrivateKey, err)
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid private key '%s': not an ECDSA key", identity.PrivateKey)
+	}
+
+	return certificate, ecdsaKey, nil
+}
+
+// signECDSA signs the digest and normalizes the signature to its low-S form, the only one accepted by Fabric.
+func signECDSA(key *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	halfOrder := new(big.Int).Rsh(key.Params().N, 1)
+	if s.Cmp(halfOrder) > 0 {
+		s.Sub(key.Params().N, s)
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
@@ -0,0 +1,167 @@
+package fabclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+func newTestIdentity(t *testing.T, dir, name string) (Identity, *ecdsa.PublicKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity := Identity{
+		Certificate: filepath.Join(dir, name+"-cert.pem"),
+		PrivateKey:  filepath.Join(dir, name+"-key.pem"),
+		Username:    name,
+	}
+
+	if err := ioutil.WriteFile(identity.Certificate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(identity.PrivateKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return identity, &key.PublicKey
+}
+
+func TestSignChannelConfigUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signatures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	original, err := extractConfigFromBlock(newTestConfigBlock(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	if err := newChannelConfigEditor(updated).SetACL("qscc/GetChainInfo", "/Channel/Application/Writers"); err != nil {
+		t.Fatal(err)
+	}
+
+	configUpdate, err := computeConfigUpdate("channelall", original, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope, err := newConfigUpdateEnvelope(configUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org1Admin, org1PublicKey := newTestIdentity(t, dir, "org1admin")
+	org2Admin, _ := newTestIdentity(t, dir, "org2admin")
+
+	org1Signed, err := SignChannelConfigUpdate(envelope, "Org1MSP", org1Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org2Signed, err := SignChannelConfigUpdate(envelope, "Org2MSP", org2Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeChannelConfigUpdateSignatures(org1Signed, org2Signed, org1Signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configUpdateEnvelope, err := decodeConfigUpdateEnvelope(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configUpdateEnvelope.Signatures) != 2 {
+		t.Fatalf("expected 2 signatures but got %d", len(configUpdateEnvelope.Signatures))
+	}
+
+	signature := configUpdateEnvelope.Signatures[0]
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, signatureHeader); err != nil {
+		t.Fatal(err)
+	}
+
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureHeader.Creator, creator); err != nil {
+		t.Fatal(err)
+	}
+
+	if creator.Mspid != "Org1MSP" || len(signatureHeader.Nonce) != nonceSize {
+		t.Errorf("unexpected signature header: %+v", signatureHeader)
+	}
+
+	var ecdsaSignature struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(signature.Signature, &ecdsaSignature); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(append(append([]byte{}, signature.SignatureHeader...), configUpdateEnvelope.ConfigUpdate...))
+	if !ecdsa.Verify(org1PublicKey, digest[:], ecdsaSignature.R, ecdsaSignature.S) {
+		t.Error("signature of Org1MSP admin should be valid")
+	}
+
+	if ecdsaSignature.S.Cmp(new(big.Int).Rsh(elliptic.P256().Params().N, 1)) > 0 {
+		t.Error("signature should be normalized to its low-S form")
+	}
+
+	otherUpdate := proto.Clone(configUpdate).(*common.ConfigUpdate)
+	otherUpdate.ChannelId = "other"
+
+	otherEnvelope, err := newConfigUpdateEnvelope(otherUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MergeChannelConfigUpdateSignatures(org1Signed, otherEnvelope); err == nil {
+		t.Error("should have returned an error, config updates differ")
+	}
+
+	if _, err := SignChannelConfigUpdate(envelope, "Org1MSP", Identity{Certificate: org1Admin.Certificate, PrivateKey: org1Admin.Certificate}); err == nil {
+		t.Error("should have returned an error, private key is invalid")
+	}
+
+	if _, err := SignChannelConfigUpdate([]byte("invalid"), "Org1MSP", org1Admin); err == nil {
+		t.Error("should have returned an error, envelope is invalid")
+	}
+}
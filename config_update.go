@@ -0,0 +1,136 @@
+package fabclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+)
+
+const adminsPolicyKey = "Admins"
+
+var errNoConfigDifferences = errors.New(_noConfigDifferences)
+
+// computeConfigUpdate computes the config update transforming the original config into the updated one, as expected by
+// the orderer. errNoConfigDifferences is returned when both configs are the same.
+func computeConfigUpdate(channelID string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	configUpdate, err := resmgmt.CalculateConfigUpdate(channelID, original, updated)
+	if err != nil && strings.Contains(err.Error(), _noConfigDifferences) {
+		return nil, errNoConfigDifferences
+	}
+
+	return configUpdate, err
+}
+
+// newConfigUpdateEnvelope wraps a config update into an envelope of type CONFIG_UPDATE, as read by resmgmt.SaveChannel.
+// The envelope carries no signature, those are collected by the SDK when saving the channel.
+func newConfigUpdateEnvelope(configUpdate *common.ConfigUpdate) ([]byte, error) {
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	configUpdateEnvelopeBytes, err := proto.Marshal(&common.ConfigUpdateEnvelope{ConfigUpdate: configUpdateBytes})
+	if err != nil {
+		return nil, err
+	}
+
+	channelHeaderBytes, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_CONFIG_UPDATE),
+		ChannelId: configUpdate.ChannelId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: &common.Header{ChannelHeader: channelHeaderBytes},
+		Data:   configUpdateEnvelopeBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(&common.Envelope{Payload: payloadBytes})
+}
+
+// findApplicationOrganization returns the application group of the organization identified by the given MSP ID. The
+// group key is the organization name from configtx.yaml, which does not necessarily match its MSP ID.
+func findApplicationOrganization(config *common.Config, mspID string) (*common.ConfigGroup, error) {
+	application := config.GetChannelGroup().GetGroups()[applicationGroupKey]
+	if application == nil {
+		return nil, errors.New("channel config has no application group")
+	}
+
+	for _, organization := range application.Groups {
+		if organization == nil {
+			continue
+		}
+
+		fabricMSPConfig, err := decodeFabricMSPConfig(organization)
+		if err != nil {
+			return nil, err
+		}
+
+		if fabricMSPConfig.Name == mspID {
+			return organization, nil
+		}
+	}
+
+	return nil, fmt.Errorf("organization '%s' not found in channel application group", mspID)
+}
+
+func decodeFabricMSPConfig(organization *common.ConfigGroup) (*msp.FabricMSPConfig, error) {
+	mspConfig := &msp.MSPConfig{}
+	if err := unmarshalConfigValue(organization, mspKey, mspConfig); err != nil {
+		return nil, err
+	}
+
+	fabricMSPConfig := &msp.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal MSP config: %w", err)
+	}
+
+	return fabricMSPConfig, nil
+}
+
+// setAnchorPeers sets the anchor peers of the organization identified by the given MSP ID.
+func setAnchorPeers(config *common.Config, mspID string, anchorPeers []*AnchorPeer) error {
+	organization, err := findApplicationOrganization(config, mspID)
+	if err != nil {
+		return err
+	}
+
+	value := &peer.AnchorPeers{AnchorPeers: make([]*peer.AnchorPeer, len(anchorPeers))}
+	for i, anchorPeer := range anchorPeers {
+		value.AnchorPeers[i] = &peer.AnchorPeer{Host: anchorPeer.Host, Port: int32(anchorPeer.Port)}
+	}
+
+	return setConfigValue(organization, anchorPeersKey, value)
+}
+
+// parseAnchorPeer parses a peer URL such as "grpcs://peer0.org1.example.com:7051" into an anchor peer.
+func parseAnchorPeer(peerURL string) (*AnchorPeer, error) {
+	if i := strings.Index(peerURL, "://"); i >= 0 {
+		peerURL = peerURL[i+len("://"):]
+	}
+
+	host, port, err := net.SplitHostPort(peerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer URL '%s': %w", peerURL, err)
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port of peer URL '%s': %w", peerURL, err)
+	}
+
+	return &AnchorPeer{Host: host, Port: portNumber}, nil
+}
@@ -0,0 +1,90 @@
+package fabclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+func TestComputeConfigUpdate(t *testing.T) {
+	original, err := extractConfigFromBlock(newTestConfigBlock(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := computeConfigUpdate("channelall", original, proto.Clone(original).(*common.Config)); !errors.Is(err, errNoConfigDifferences) {
+		t.Errorf("should have returned errNoConfigDifferences but got: %v", err)
+	}
+
+	if _, err := computeConfigUpdate("", original, original); err == nil || errors.Is(err, errNoConfigDifferences) {
+		t.Errorf("should have returned the error of the SDK, channel ID is missing, but got: %v", err)
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	if err := setAnchorPeers(updated, "Org2MSP", []*AnchorPeer{{Host: "peer0.org2.dummy.com", Port: 9051}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := setAnchorPeers(updated, "Org3MSP", nil); err == nil {
+		t.Error("should have returned an error, organization 'Org3MSP' is not part of the channel")
+	}
+
+	configUpdate, err := computeConfigUpdate("channelall", original, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if configUpdate.ChannelId != "channelall" {
+		t.Errorf("unexpected channel ID: %s", configUpdate.ChannelId)
+	}
+
+	writeSet := configUpdate.WriteSet.Groups[applicationGroupKey].Groups["Org2MSP"]
+	if writeSet == nil || writeSet.Version != 1 || writeSet.Values[anchorPeersKey] == nil {
+		t.Fatalf("unexpected write set: %+v", configUpdate.WriteSet)
+	}
+
+	if _, ok := configUpdate.WriteSet.Groups[applicationGroupKey].Groups["Org1MSP"]; ok {
+		t.Error("unchanged organization 'Org1MSP' should not be part of the write set")
+	}
+
+	if readSet := configUpdate.ReadSet.Groups[applicationGroupKey].Groups["Org2MSP"]; readSet == nil || readSet.Version != 0 || readSet.Values[mspKey] == nil {
+		t.Errorf("unexpected read set: %+v", configUpdate.ReadSet)
+	}
+
+	anchorPeers := &peer.AnchorPeers{}
+	if err := proto.Unmarshal(writeSet.Values[anchorPeersKey].Value, anchorPeers); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(anchorPeers.AnchorPeers) != 1 || anchorPeers.AnchorPeers[0].Host != "peer0.org2.dummy.com" || anchorPeers.AnchorPeers[0].Port != 9051 {
+		t.Errorf("unexpected anchor peers: %v", anchorPeers.AnchorPeers)
+	}
+
+	envelope, err := newConfigUpdateEnvelope(configUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(envelope) == 0 {
+		t.Error("config update envelope should not be empty")
+	}
+}
+
+func TestParseAnchorPeer(t *testing.T) {
+	anchorPeer, err := parseAnchorPeer("grpcs://peer0.org1.dummy.com:7051")
+	if err != nil || anchorPeer.Host != "peer0.org1.dummy.com" || anchorPeer.Port != 7051 {
+		t.Errorf("unexpected anchor peer %+v: %v", anchorPeer, err)
+	}
+
+	anchorPeer, err = parseAnchorPeer("peer0.org1.dummy.com:7051")
+	if err != nil || anchorPeer.Host != "peer0.org1.dummy.com" || anchorPeer.Port != 7051 {
+		t.Errorf("unexpected anchor peer %+v: %v", anchorPeer, err)
+	}
+
+	if _, err := parseAnchorPeer("peer0.org1.dummy.com"); err == nil {
+		t.Error("should have returned an error, peer URL has no port")
+	}
+}
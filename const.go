@@ -3,4 +3,8 @@ package fabclient
 const (
 	_channelAlreadyExists = "be at version 0, but it is currently at version"
 	_channelAlreadyJoined = "LedgerID already exists"
+
+	_ordererChannelAlreadyJoined = "channel already exists"
+
+	_noConfigDifferences = "no differences detected between original and updated config"
 )
@@ -0,0 +1,244 @@
+package fabclient
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+)
+
+var (
+	// ErrAmbiguousChannel is returned when a request does not target a specific channel (see WithChannelContext) while
+	// several channels are attached to the client.
+	ErrAmbiguousChannel = errors.New("cannot determine channel context")
+	// ErrChannelAlreadyExists is returned when creating a channel which already exists, for instance when joining an
+	// orderer to a channel it is already a member or a follower of.
+	ErrChannelAlreadyExists = errors.New("channel already exists")
+	// ErrCommitStatusUnknown is returned by Commit.Wait when the commit event of a transaction submitted asynchronously
+	// has not been received, the transaction may thus still be committed.
+	ErrCommitStatusUnknown = errors.New("commit status of the transaction is unknown")
+	// ErrChannelNotFound is returned when a request targets a channel which is not attached to the client.
+	ErrChannelNotFound = errors.New("channel not found")
+	// ErrUserNotFound is returned when a request targets a user unknown to the client (see WithUserContext).
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// PeerStatus is the status returned by a peer, or inferred by the SDK, when a peer failed to endorse a proposal. Peer is
+// empty when the failure cannot be attributed to a specific peer.
+type PeerStatus struct {
+	Peer    string
+	Group   string
+	Code    int32
+	Message string
+
+	status *status.Status
+}
+
+// EndorsementError is returned when a transaction proposal could not be endorsed, it carries the status of each peer
+// which rejected the proposal or could not be reached.
+type EndorsementError struct {
+	Peers []PeerStatus
+
+	err error
+}
+
+// Error returns the error reported by the SDK.
+func (e *EndorsementError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error reported by the SDK.
+func (e *EndorsementError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the error reported by the SDK, the status it carries can thus still be retrieved with status.FromError.
+func (e *EndorsementError) Cause() error {
+	return e.err
+}
+
+// Retryable reports whether the endorsement may succeed if the proposal is sent again, i.e. all the peers failed for a
+// transient reason such as being unavailable.
+func (e *EndorsementError) Retryable() bool {
+	if len(e.Peers) == 0 {
+		return false
+	}
+
+	for _, peerStatus := range e.Peers {
+		if !isRetryableStatus(peerStatus.status) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TransactionValidationError is returned when a transaction has been ordered but invalidated by the peers when
+// committing it, Code holds the validation code (e.g. MVCC_READ_CONFLICT, ENDORSEMENT_POLICY_FAILURE).
+type TransactionValidationError struct {
+	TransactionID string
+	Code          TxValidationCode
+
+	err error
+}
+
+// Error returns the error reported by the SDK.
+func (e *TransactionValidationError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error reported by the SDK.
+func (e *TransactionValidationError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the error reported by the SDK, the status it carries can thus still be retrieved with status.FromError.
+func (e *TransactionValidationError) Cause() error {
+	return e.err
+}
+
+// Retryable reports whether the transaction may be committed if submitted again, i.e. it has been invalidated because of
+// a read conflict with a concurrent transaction.
+func (e *TransactionValidationError) Retryable() bool {
+	switch peer.TxValidationCode(e.Code) {
+	case peer.TxValidationCode_MVCC_READ_CONFLICT, peer.TxValidationCode_PHANTOM_READ_CONFLICT:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether the failed request may succeed if performed again. Timeouts, unavailable peers or orderers
+// and read conflicts (MVCC_READ_CONFLICT, PHANTOM_READ_CONFLICT) are retryable, whereas chaincode errors, endorsement
+// policy failures (ENDORSEMENT_POLICY_FAILURE) and cancellations are not.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var validationError *TransactionValidationError
+	if errors.As(err, &validationError) {
+		return validationError.Retryable()
+	}
+
+	var endorsementError *EndorsementError
+	if errors.As(err, &endorsementError) {
+		return endorsementError.Retryable()
+	}
+
+	statuses := findStatuses(err)
+	if len(statuses) == 0 {
+		return false
+	}
+
+	for _, s := range statuses {
+		if !isRetryableStatus(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// convertChaincodeError converts the error returned by the SDK when invoking or querying a chaincode into an
+// EndorsementError or a TransactionValidationError, other errors are returned as is.
+func convertChaincodeError(txID string, err error) error {
+	statuses := findStatuses(err)
+	if len(statuses) == 0 {
+		return err
+	}
+
+	if len(statuses) == 1 && statuses[0].Group == status.EventServerStatus {
+		return &TransactionValidationError{
+			TransactionID: txID,
+			Code:          TxValidationCode(statuses[0].Code),
+			err:           err,
+		}
+	}
+
+	peers := make([]PeerStatus, 0, len(statuses))
+	for _, s := range statuses {
+		switch s.Group {
+		case status.EndorserServerStatus, status.EndorserClientStatus, status.ChaincodeStatus:
+		default:
+			return err
+		}
+
+		peerStatus := PeerStatus{
+			Group:   s.Group.String(),
+			Code:    s.Code,
+			Message: s.Message,
+			status:  s,
+		}
+
+		if len(s.Details) > 0 {
+			if peerURL, ok := s.Details[0].(string); ok {
+				peerStatus.Peer = peerURL
+			}
+		}
+
+		peers = append(peers, peerStatus)
+	}
+
+	return &EndorsementError{Peers: peers, err: err}
+}
+
+// findStatuses returns the statuses carried by an error returned by the SDK, an error may hold several statuses when
+// it has been returned by several peers.
+func findStatuses(err error) []*status.Status {
+	for err != nil {
+		switch e := err.(type) {
+		case *status.Status:
+			return []*status.Status{e}
+		case multi.Errors:
+			var statuses []*status.Status
+			for _, nested := range e {
+				nestedStatuses := findStatuses(nested)
+				if len(nestedStatuses) == 0 {
+					return nil
+				}
+
+				statuses = append(statuses, nestedStatuses...)
+			}
+
+			return statuses
+		}
+
+		if unwrapped := errors.Unwrap(err); unwrapped != nil {
+			err = unwrapped
+			continue
+		}
+
+		causer, ok := err.(interface{ Cause() error })
+		if !ok || causer.Cause() == err {
+			return nil
+		}
+
+		err = causer.Cause()
+	}
+
+	return nil
+}
+
+// transientCodes are the status codes, mapped by group, of the errors considered as retryable by IsRetryable.
+var transientCodes = RetryTransientErrors.codes()
+
+func isRetryableStatus(s *status.Status) bool {
+	return s != nil && isRetryableCode(transientCodes, s)
+}
+
+// ignoreChannelAlreadyExists returns nil when err reports that the channel already exists, creating and joining channels
+// being idempotent.
+func ignoreChannelAlreadyExists(err error) error {
+	if errors.Is(err, ErrChannelAlreadyExists) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,124 @@
+package fabclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+// causeError mimics the errors wrapped by the SDK with github.com/pkg/errors, which expose a Cause method but no Unwrap.
+type causeError struct {
+	message string
+	cause   error
+}
+
+func (e *causeError) Error() string { return e.message + ": " + e.cause.Error() }
+
+func (e *causeError) Cause() error { return e.cause }
+
+func TestConvertChaincodeError(t *testing.T) {
+	if err := convertChaincodeError("", nil); err != nil {
+		t.Errorf("should have returned nil but got %v", err)
+	}
+
+	witness := errors.New("witness")
+	if err := convertChaincodeError("", witness); err != witness {
+		t.Errorf("should have returned %v but got %v", witness, err)
+	}
+
+	unavailable := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection refused", []interface{}{"peer0.org1.dummy.com:7051"})
+	chaincodeError := status.New(status.ChaincodeStatus, 500, "asset not found", nil)
+	proposalError := status.New(status.EndorserServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "shutting down", []interface{}{"peer1.org1.dummy.com:8051"})
+
+	err := convertChaincodeError("", &causeError{"Failed to execute transaction", multi.New(unavailable, chaincodeError)})
+
+	var endorsementError *EndorsementError
+	if !errors.As(err, &endorsementError) {
+		t.Fatalf("should have returned an endorsement error but got %T", err)
+	}
+
+	if len(endorsementError.Peers) != 2 || endorsementError.Peers[0].Peer != "peer0.org1.dummy.com:7051" || endorsementError.Peers[1].Message != "asset not found" {
+		t.Errorf("unexpected peer statuses: %+v", endorsementError.Peers)
+	}
+
+	if endorsementError.Retryable() || IsRetryable(err) {
+		t.Error("should not be retryable, the chaincode returned an error")
+	}
+
+	if s, ok := status.FromError(err); !ok || s.Code != status.MultipleErrors.ToInt32() {
+		t.Errorf("status should still be retrievable from the error: %v", s)
+	}
+
+	err = convertChaincodeError("", multi.New(unavailable, proposalError))
+	if !errors.As(err, &endorsementError) || !IsRetryable(err) {
+		t.Errorf("should be a retryable endorsement error: %v", err)
+	}
+
+	err = convertChaincodeError("txID", status.New(status.EventServerStatus, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), "received invalid transaction", nil))
+
+	var validationError *TransactionValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("should have returned a transaction validation error but got %T", err)
+	}
+
+	if validationError.TransactionID != "txID" || validationError.Code.String() != "MVCC_READ_CONFLICT" || !IsRetryable(err) {
+		t.Errorf("unexpected transaction validation error: %+v", validationError)
+	}
+
+	err = convertChaincodeError("txID", status.New(status.EventServerStatus, int32(peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "received invalid transaction", nil))
+	if !errors.As(err, &validationError) || IsRetryable(err) {
+		t.Errorf("should be a non retryable transaction validation error: %v", err)
+	}
+
+	timeout := status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out", nil)
+	if err := convertChaincodeError("", timeout); err != timeout {
+		t.Errorf("should have returned %v but got %v", timeout, err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		err       error
+		retryable bool
+	}{
+		{nil, false},
+		{errors.New("witness"), false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("failed: %w", context.DeadlineExceeded), true},
+		{status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out", nil), true},
+		{status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "connection refused", nil), true},
+		{status.New(status.GRPCTransportStatus, int32(grpcCodes.PermissionDenied), "access denied", nil), false},
+		{status.New(status.OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "no leader", nil), true},
+		{status.New(status.OrdererServerStatus, int32(common.Status_FORBIDDEN), "access denied", nil), false},
+		{status.New(status.EndorserServerStatus, int32(common.Status_INTERNAL_SERVER_ERROR), "chaincode error", nil), false},
+		{status.New(status.ChaincodeStatus, 500, "asset not found", nil), false},
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_PHANTOM_READ_CONFLICT), "invalid transaction", nil), true},
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "invalid transaction", nil), false},
+		{&causeError{"failed", status.New(status.DiscoveryServerStatus, status.QueryEndorsers.ToInt32(), "no endorsers", nil)}, true},
+	}
+
+	for _, testCase := range testCases {
+		if retryable := IsRetryable(testCase.err); retryable != testCase.retryable {
+			t.Errorf("expected %v to be retryable: %t, got %t", testCase.err, testCase.retryable, retryable)
+		}
+	}
+}
+
+func TestIgnoreChannelAlreadyExists(t *testing.T) {
+	if err := ignoreChannelAlreadyExists(fmt.Errorf("failed to save channel 'channelall': %w", ErrChannelAlreadyExists)); err != nil {
+		t.Errorf("should have ignored the error, channel already exists: %v", err)
+	}
+
+	witness := errors.New("witness")
+	if err := ignoreChannelAlreadyExists(witness); err != witness {
+		t.Errorf("should have returned %v but got %v", witness, err)
+	}
+}
@@ -0,0 +1,172 @@
+package fabclient
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var (
+	// ErrEventServiceClosed is returned by EventRegistration.Err when the event service closed the registration, for instance
+	// once the connection to the peers has been lost and could not be re-established.
+	ErrEventServiceClosed = errors.New("registration closed by the event service")
+	// ErrSlowConsumer is returned by EventRegistration.Err when the registration has been closed because its event channel
+	// was full and the overflow policy is OverflowFail.
+	ErrSlowConsumer = errors.New("registration closed, the event channel is full")
+)
+
+// OverflowPolicy defines the behavior of an event registration when its event channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for the consumer to read the event channel. It is the default policy.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest event of the channel to make room for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the new event.
+	OverflowDropNewest
+	// OverflowFail closes the registration, Err returns ErrSlowConsumer.
+	OverflowFail
+)
+
+// EventRegistration is returned when registering for events. Unregister must be called when the registration is no longer needed.
+type EventRegistration struct {
+	err        error
+	mutex      sync.RWMutex
+	once       sync.Once
+	unregister func()
+}
+
+func newEventRegistration(unregister func()) *EventRegistration {
+	return &EventRegistration{
+		unregister: unregister,
+	}
+}
+
+// Err returns the error which closed the event channel, nil if the registration is ongoing or has been unregistered.
+func (reg *EventRegistration) Err() error {
+	reg.mutex.RLock()
+	defer reg.mutex.RUnlock()
+
+	return reg.err
+}
+
+// Unregister removes the registration and closes the event channel. It is safe to call it more than once.
+func (reg *EventRegistration) Unregister() {
+	reg.once.Do(reg.unregister)
+}
+
+func (reg *EventRegistration) fail(err error) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+
+	reg.err = err
+}
+
+// eventSink delivers the events to the event channel of a registration, whatever the type of its events.
+type eventSink struct {
+	events reflect.Value
+}
+
+// newEventSink returns the sink of the given event channel.
+func newEventSink(events interface{}) eventSink {
+	return eventSink{events: reflect.ValueOf(events)}
+}
+
+// send blocks until the event is delivered or stop is closed, it returns false in the latter case.
+func (s eventSink) send(event interface{}, stop <-chan struct{}) bool {
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: s.events, Send: reflect.ValueOf(event)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+	})
+
+	return chosen == 0
+}
+
+func (s eventSink) trySend(event interface{}) bool {
+	return s.events.TrySend(reflect.ValueOf(event))
+}
+
+func (s eventSink) dropOldest() {
+	s.events.TryRecv()
+}
+
+func (s eventSink) close() {
+	s.events.Close()
+}
+
+type ongoingRegistration struct {
+	chaincodeEvent    *chaincodeEventKey
+	eventRegistration *EventRegistration
+	eventService      fab.EventService
+	policy            OverflowPolicy
+	registration      fab.Registration
+	release           func()
+	sink              eventSink
+	stop              chan struct{}
+	done              chan struct{}
+}
+
+// forward runs the delivery loop of the registration until it is unregistered or closed by the event service.
+// next waits for the next event coming from the event service, process delivers it to the sink.
+func (ongoing *ongoingRegistration) forward(next func() (interface{}, bool), process func(event interface{}) bool) {
+	defer ongoing.terminate()
+
+	for {
+		event, ok := next()
+		if !ok {
+			if !ongoing.stopped() {
+				ongoing.eventRegistration.fail(ErrEventServiceClosed)
+			}
+
+			return
+		}
+
+		if !process(event) {
+			return
+		}
+	}
+}
+
+// deliver sends the event to the sink according to the overflow policy. It returns false once the registration is over.
+func (ongoing *ongoingRegistration) deliver(event interface{}) bool {
+	switch ongoing.policy {
+	case OverflowDropOldest:
+		if !ongoing.sink.trySend(event) {
+			ongoing.sink.dropOldest()
+			ongoing.sink.trySend(event)
+		}
+	case OverflowDropNewest:
+		ongoing.sink.trySend(event)
+	case OverflowFail:
+		if !ongoing.sink.trySend(event) {
+			ongoing.eventRegistration.fail(ErrSlowConsumer)
+			return false
+		}
+	default:
+		return ongoing.sink.send(event, ongoing.stop)
+	}
+
+	return true
+}
+
+func (ongoing *ongoingRegistration) stopped() bool {
+	select {
+	case <-ongoing.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ongoing *ongoingRegistration) terminate() {
+	ongoing.sink.close()
+	close(ongoing.done)
+
+	// the registration ended on its own, the underlying registration still has to be released
+	if !ongoing.stopped() {
+		go ongoing.eventRegistration.Unregister()
+	}
+}
@@ -0,0 +1,94 @@
+package fabclient
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestRegistration(size int, policy OverflowPolicy) (chan *TxStatusEvent, *ongoingRegistration) {
+	ch := make(chan *TxStatusEvent, size)
+	ongoing := &ongoingRegistration{
+		eventRegistration: newEventRegistration(func() {}),
+		policy:            policy,
+		sink:              newEventSink(ch),
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+
+	return ch, ongoing
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	ch, ongoing := newTestRegistration(2, OverflowDropOldest)
+	for i := uint64(1); i <= 3; i++ {
+		if !ongoing.deliver(&TxStatusEvent{BlockNumber: i}) {
+			t.Fatal("drop oldest policy should not end the registration")
+		}
+	}
+
+	if first, second := <-ch, <-ch; first.BlockNumber != 2 || second.BlockNumber != 3 {
+		t.Errorf("expected events of blocks 2 and 3 but got %d and %d", first.BlockNumber, second.BlockNumber)
+	}
+
+	ch, ongoing = newTestRegistration(2, OverflowDropNewest)
+	for i := uint64(1); i <= 3; i++ {
+		if !ongoing.deliver(&TxStatusEvent{BlockNumber: i}) {
+			t.Fatal("drop newest policy should not end the registration")
+		}
+	}
+
+	if first, second := <-ch, <-ch; first.BlockNumber != 1 || second.BlockNumber != 2 {
+		t.Errorf("expected events of blocks 1 and 2 but got %d and %d", first.BlockNumber, second.BlockNumber)
+	}
+
+	_, ongoing = newTestRegistration(1, OverflowFail)
+	if !ongoing.deliver(&TxStatusEvent{BlockNumber: 1}) {
+		t.Fatal("fail policy should not end the registration while the channel has room")
+	}
+
+	if ongoing.deliver(&TxStatusEvent{BlockNumber: 2}) {
+		t.Fatal("fail policy should end the registration once the channel is full")
+	}
+
+	if !errors.Is(ongoing.eventRegistration.Err(), ErrSlowConsumer) {
+		t.Errorf("expected ErrSlowConsumer but got %v", ongoing.eventRegistration.Err())
+	}
+
+	_, ongoing = newTestRegistration(0, OverflowBlock)
+	close(ongoing.stop)
+	if ongoing.deliver(&TxStatusEvent{BlockNumber: 1}) {
+		t.Fatal("block policy should give up once the registration is stopped")
+	}
+}
+
+func TestForward(t *testing.T) {
+	unregistered := make(chan struct{})
+	ch, ongoing := newTestRegistration(1, OverflowBlock)
+	ongoing.eventRegistration = newEventRegistration(func() { close(unregistered) })
+
+	events := make(chan interface{}, 1)
+	events <- &TxStatusEvent{BlockNumber: 1}
+	close(events)
+
+	go ongoing.forward(
+		func() (interface{}, bool) {
+			event, ok := <-events
+			return event, ok
+		},
+		ongoing.deliver,
+	)
+
+	if event := <-ch; event.BlockNumber != 1 {
+		t.Errorf("expected event of block 1 but got %d", event.BlockNumber)
+	}
+
+	if _, ok := <-ch; ok {
+		t.Fatal("event channel should have been closed")
+	}
+
+	if !errors.Is(ongoing.eventRegistration.Err(), ErrEventServiceClosed) {
+		t.Errorf("expected ErrEventServiceClosed but got %v", ongoing.eventRegistration.Err())
+	}
+
+	<-unregistered
+}
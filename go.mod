@@ -3,8 +3,10 @@ module github.com/TommyStarK/fabric-go-client
 go 1.15
 
 require (
+	github.com/golang/protobuf v1.3.3
 	github.com/hyperledger/fabric-protos-go v0.0.0-20200707132912-fee30f3ccd23
 	github.com/hyperledger/fabric-sdk-go v1.0.0
 	github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric v0.0.0-20190822125948-d2b42602e52e
+	google.golang.org/grpc v1.29.1
 	gopkg.in/yaml.v2 v2.3.0
 )
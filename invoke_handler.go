@@ -0,0 +1,180 @@
+package fabclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// commitHandler sends the endorsed transaction to the orderer and waits for it to be committed, as the commit handler of
+// the SDK does, while recording the number of the block the transaction has been committed in. When a timeout is given,
+// it stops waiting for the commit once it expires.
+type commitHandler struct {
+	blockNumber uint64
+	timeout     time.Duration
+}
+
+// newExecuteHandler returns the handler endorsing the proposal and validating the endorsements before handing the
+// transaction over to next, which submits it.
+func newExecuteHandler(next invoke.Handler) invoke.Handler {
+	return invoke.NewProposalProcessorHandler(
+		invoke.NewEndorsementHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(next),
+			),
+		),
+	)
+}
+
+func (h *commitHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	txID := string(requestContext.Response.TransactionID)
+
+	registration, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		requestContext.Error = fmt.Errorf("error registering for TxStatus event: %w", err)
+		return
+	}
+
+	defer clientContext.EventService.Unregister(registration)
+
+	if err := sendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses); err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	var timeout <-chan time.Time
+	if h.timeout > 0 {
+		timer := time.NewTimer(h.timeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case txStatus := <-statusNotifier:
+		requestContext.Response.TxValidationCode = txStatus.TxValidationCode
+		h.blockNumber = txStatus.BlockNumber
+
+		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
+			requestContext.Error = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode), "received invalid transaction", nil)
+		}
+	case <-timeout:
+		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(), "commit timeout expired before receiving block event", nil)
+	case <-requestContext.Ctx.Done():
+		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(), "Execute didn't receive block event", nil)
+	}
+}
+
+// submitHandler sends the endorsed transaction to the orderer without waiting for it to be committed. It registers for
+// the status of the transaction beforehand so that the commit event cannot be missed, the registration is then handed
+// over to the caller. Since the SDK stops waiting for the handler once the request context is done, the caller may
+// give up on the request while the handler is still running: the transaction is then not sent if it can still be
+// avoided, and the registration is released either by the caller or by the handler, whichever comes last.
+type submitHandler struct {
+	eventService   fab.EventService
+	registration   fab.Registration
+	statusNotifier <-chan *fab.TxStatusEvent
+
+	abandoned bool
+	mutex     sync.Mutex
+}
+
+func (h *submitHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	txID := string(requestContext.Response.TransactionID)
+
+	registration, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		requestContext.Error = fmt.Errorf("error registering for TxStatus event: %w", err)
+		return
+	}
+
+	if requestContext.Ctx.Err() != nil {
+		clientContext.EventService.Unregister(registration)
+		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out or been cancelled before submitting the transaction", nil)
+		return
+	}
+
+	if err := sendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses); err != nil {
+		clientContext.EventService.Unregister(registration)
+		requestContext.Error = err
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.abandoned {
+		clientContext.EventService.Unregister(registration)
+		return
+	}
+
+	h.eventService = clientContext.EventService
+	h.registration = registration
+	h.statusNotifier = statusNotifier
+}
+
+// release unregisters the status registration if it has been handed over, a registration handed over afterwards is
+// unregistered by the handler itself.
+func (h *submitHandler) release() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.abandoned = true
+
+	if h.registration != nil {
+		h.eventService.Unregister(h.registration)
+		h.registration = nil
+	}
+}
+
+// newEndorsedHandler returns the handler validating the endorsements of a transaction endorsed beforehand, as
+// newExecuteHandler does, before handing the transaction over to next, which submits it.
+func newEndorsedHandler(proposal *fab.TransactionProposal, responses []*fab.TransactionProposalResponse, next invoke.Handler) invoke.Handler {
+	return &endorsedHandler{
+		proposal:  proposal,
+		responses: responses,
+		next: invoke.NewEndorsementValidationHandler(
+			invoke.NewSignatureValidationHandler(next),
+		),
+	}
+}
+
+// endorsedHandler hands a transaction endorsed beforehand over to next, which submits it, instead of collecting the
+// endorsements.
+type endorsedHandler struct {
+	proposal  *fab.TransactionProposal
+	responses []*fab.TransactionProposalResponse
+	next      invoke.Handler
+}
+
+func (h *endorsedHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	requestContext.Response.Proposal = h.proposal
+	requestContext.Response.Responses = h.responses
+	requestContext.Response.TransactionID = h.proposal.TxnID
+	requestContext.Response.Payload = h.responses[0].ProposalResponse.GetResponse().GetPayload()
+	requestContext.Response.ChaincodeStatus = h.responses[0].ChaincodeStatus
+
+	h.next.Handle(requestContext, clientContext)
+}
+
+// sendTransaction assembles the endorsed transaction and broadcasts it to the orderer.
+func sendTransaction(sender fab.Sender, proposal *fab.TransactionProposal, responses []*fab.TransactionProposalResponse) error {
+	tx, err := sender.CreateTransaction(fab.TransactionRequest{
+		Proposal:          proposal,
+		ProposalResponses: responses,
+	})
+	if err != nil {
+		return fmt.Errorf("CreateTransaction failed: %w", err)
+	}
+
+	if _, err := sender.SendTransaction(tx); err != nil {
+		return fmt.Errorf("SendTransaction failed: %w", err)
+	}
+
+	return nil
+}
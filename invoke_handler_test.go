@@ -0,0 +1,187 @@
+package fabclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+type testEventService struct {
+	fab.EventService
+
+	registered   int
+	unregistered int
+	mutex        sync.Mutex
+}
+
+func (s *testEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.registered++
+	return txID, make(chan *fab.TxStatusEvent, 1), nil
+}
+
+func (s *testEventService) Unregister(reg fab.Registration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unregistered++
+}
+
+func (s *testEventService) counts() (int, int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.registered, s.unregistered
+}
+
+// testTransactor blocks the broadcast of the transaction until proceed is closed, sending is closed once the
+// broadcast started.
+type testTransactor struct {
+	fab.Transactor
+
+	sending chan struct{}
+	proceed chan struct{}
+	sent    int
+}
+
+func (t *testTransactor) CreateTransaction(request fab.TransactionRequest) (*fab.Transaction, error) {
+	return &fab.Transaction{}, nil
+}
+
+func (t *testTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	close(t.sending)
+	<-t.proceed
+	t.sent++
+	return &fab.TransactionResponse{}, nil
+}
+
+func newTestSubmitContexts(ctx context.Context) (*invoke.RequestContext, *invoke.ClientContext, *testEventService, *testTransactor) {
+	eventService := &testEventService{}
+	transactor := &testTransactor{sending: make(chan struct{}), proceed: make(chan struct{})}
+
+	requestContext := &invoke.RequestContext{
+		Ctx:      ctx,
+		Response: invoke.Response{TransactionID: "txid"},
+	}
+
+	return requestContext, &invoke.ClientContext{EventService: eventService, Transactor: transactor}, eventService, transactor
+}
+
+func TestSubmitHandler(t *testing.T) {
+	// the transaction is submitted and the registration handed over until released
+	requestContext, clientContext, eventService, transactor := newTestSubmitContexts(context.Background())
+	close(transactor.proceed)
+
+	submit := &submitHandler{}
+	submit.Handle(requestContext, clientContext)
+
+	if requestContext.Error != nil || transactor.sent != 1 || submit.registration == nil || submit.statusNotifier == nil {
+		t.Fatalf("transaction should have been submitted, error: %v", requestContext.Error)
+	}
+
+	submit.release()
+	if _, unregistered := eventService.counts(); unregistered != 1 || submit.registration != nil {
+		t.Error("registration should have been unregistered once released")
+	}
+
+	// the request context is done before the transaction is submitted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requestContext, clientContext, eventService, transactor = newTestSubmitContexts(ctx)
+	close(transactor.proceed)
+
+	submit = &submitHandler{}
+	submit.Handle(requestContext, clientContext)
+
+	if requestContext.Error == nil || !IsRetryable(requestContext.Error) {
+		t.Errorf("should have returned a timeout error, got: %v", requestContext.Error)
+	}
+
+	if registered, unregistered := eventService.counts(); transactor.sent != 0 || registered != 1 || unregistered != 1 {
+		t.Errorf("transaction should not have been submitted and its registration should have been unregistered, sent: %d, unregistered: %d", transactor.sent, unregistered)
+	}
+
+	// the request is cancelled while the transaction is being submitted
+	ctx, cancel = context.WithCancel(context.Background())
+
+	requestContext, clientContext, eventService, transactor = newTestSubmitContexts(ctx)
+
+	submit = &submitHandler{}
+	handled := make(chan struct{})
+	go func() {
+		submit.Handle(requestContext, clientContext)
+		close(handled)
+	}()
+
+	select {
+	case <-transactor.sending:
+	case <-time.After(time.Second):
+		t.Fatal("transaction should have been submitted")
+	}
+
+	// the SDK gives up on the handler once the request context is done, submitAsync then releases the registration
+	cancel()
+	submit.release()
+	close(transactor.proceed)
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("handler should have returned")
+	}
+
+	if registered, unregistered := eventService.counts(); registered != 1 || unregistered != 1 || submit.registration != nil {
+		t.Errorf("registration of the abandoned request should have been unregistered, unregistered: %d", unregistered)
+	}
+}
+
+type testHandler struct {
+	handled bool
+}
+
+func (h *testHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	h.handled = true
+}
+
+func newTestProposalResponse(endorser string, status int32, payload string) *fab.TransactionProposalResponse {
+	return &fab.TransactionProposalResponse{
+		Endorser: endorser,
+		Status:   status,
+		ProposalResponse: &peer.ProposalResponse{
+			Payload:     []byte(payload),
+			Response:    &peer.Response{Status: status, Payload: []byte(payload)},
+			Endorsement: &peer.Endorsement{Endorser: []byte(endorser)},
+		},
+	}
+}
+
+func TestEndorsedHandler(t *testing.T) {
+	proposal := &fab.TransactionProposal{TxnID: "txid"}
+
+	// the endorsements must be validated before the transaction is submitted
+	for _, responses := range [][]*fab.TransactionProposalResponse{
+		{newTestProposalResponse("peer0", 200, "result"), newTestProposalResponse("peer1", 200, "tampered")},
+		{newTestProposalResponse("peer0", 200, "result"), newTestProposalResponse("peer1", 500, "result")},
+	} {
+		next := &testHandler{}
+		requestContext := &invoke.RequestContext{Ctx: context.Background()}
+
+		newEndorsedHandler(proposal, responses, next).Handle(requestContext, &invoke.ClientContext{})
+
+		if requestContext.Error == nil || next.handled {
+			t.Errorf("endorsements should have been rejected, error: %v", requestContext.Error)
+		}
+
+		if requestContext.Response.TransactionID != "txid" {
+			t.Errorf("unexpected transaction id: %s", requestContext.Response.TransactionID)
+		}
+	}
+}
@@ -22,3 +22,73 @@ func testMembershipServiceProvider(t *testing.T, msp membershipServiceProvider,
 	}
 
 }
+
+func manageUsers(t *testing.T, client *Client) {
+	admin := client.Config().Identities.Admin
+	user := client.Config().Identities.Users[0]
+
+	if err := client.AddUser(user); err == nil {
+		t.Errorf("should have returned an error, user '%s' already exists", user.Username)
+	}
+
+	if err := client.AddUser(Identity{Username: "dummy", Certificate: "/dummy", PrivateKey: "/dummy"}); err == nil {
+		t.Error("should have returned an error, we provided a wrong certificate path")
+	}
+
+	if err := client.AddUser(Identity{Username: "Operator", Certificate: admin.Certificate, PrivateKey: admin.PrivateKey}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.QueryInfo(WithUserContext("Operator")); err != nil {
+		t.Error(err)
+	}
+
+	if err := client.RotateUser("dummy", user.Certificate, user.PrivateKey); err == nil {
+		t.Error("should have returned an error, user 'dummy' does not exist")
+	}
+
+	if err := client.RotateUser("Operator", "/dummy", "/dummy"); err == nil {
+		t.Error("should have returned an error, we provided a wrong certificate path")
+	}
+
+	if _, err := client.QueryInfo(WithUserContext("Operator")); err != nil {
+		t.Errorf("failed rotation should have kept the previous handlers: %v", err)
+	}
+
+	blocks, _, err := client.RegisterBlockEvent(WithUserContext("Operator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RotateUser("Operator", user.Certificate, user.PrivateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := <-blocks; ok {
+		t.Error("block event channel registered with the previous identity should have been closed")
+	}
+
+	if _, err := client.QueryInfo(WithUserContext("Operator")); err != nil {
+		t.Error(err)
+	}
+
+	if err := client.RemoveUser("Operator"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.QueryInfo(WithUserContext("Operator")); err == nil {
+		t.Error("should have returned an error, user 'Operator' has been removed")
+	}
+
+	if err := client.RemoveUser("Operator"); err == nil {
+		t.Error("should have returned an error, user 'Operator' does not exist anymore")
+	}
+
+	if err := client.RemoveUser(user.Username); err == nil {
+		t.Errorf("should have returned an error, '%s' is the last user", user.Username)
+	}
+
+	if len(client.Config().Identities.Users) != 1 {
+		t.Errorf("expected 1 user but got %d", len(client.Config().Identities.Users))
+	}
+}
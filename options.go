@@ -1,10 +1,29 @@
 package fabclient
 
-import "time"
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+)
 
 type options struct {
+	autoAttachChannels     bool
 	channelID              string
+	checkpointer           Checkpointer
+	commitTimeout          time.Duration
+	endorsementTimeout     time.Duration
+	eventBufferSize        int
+	overflowPolicy         OverflowPolicy
 	ordererResponseTimeout time.Duration
+	queryTimeout           time.Duration
+	retryPolicy            *RetryPolicy
+	seekType               seek.Type
+	startBlock             uint64
+	subscriber             string
+	subscriptionID         string
+	targetFilter           func(Peer) bool
+	targetOrgs             []string
+	targetPeers            []string
 	userIdentity           string
 }
 
@@ -19,6 +38,15 @@ func (f optionFunc) apply(o *options) {
 	f(o)
 }
 
+// WithAutoAttachChannels allows NewClient to attach the channels listed in the configuration (i.e. Config.Channels) as
+// well as the channels already joined by the peers of the organization, so that a client started against an existing
+// network can interact with them without joining them again.
+func WithAutoAttachChannels() Option {
+	return optionFunc(func(o *options) {
+		o.autoAttachChannels = true
+	})
+}
+
 // WithChannelContext allows to target a specific channel.
 func WithChannelContext(channelID string) Option {
 	return optionFunc(func(o *options) {
@@ -26,6 +54,51 @@ func WithChannelContext(channelID string) Option {
 	})
 }
 
+// WithCheckpointer allows a chaincode event registration to record its progress under the given subscription ID.
+// A registration created with the same checkpointer and subscription ID resumes right after the last processed transaction,
+// the events already delivered are skipped. It takes precedence over the seek options once a checkpoint has been saved.
+func WithCheckpointer(checkpointer Checkpointer, subscriptionID string) Option {
+	return optionFunc(func(o *options) {
+		o.checkpointer = checkpointer
+		o.subscriptionID = subscriptionID
+	})
+}
+
+// WithCommitTimeout allows to specify how long Invoke waits for the transaction to be committed once it has been sent
+// to the orderer, the timeout of the execution of the transaction (see WithEndorsementTimeout) being extended
+// accordingly. It also bounds the wait for the commit of a transaction submitted by SubmitAsync.
+func WithCommitTimeout(timeout time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.commitTimeout = timeout
+	})
+}
+
+// WithEndorsementTimeout allows to specify a timeout for the execution of a transaction by Invoke, i.e. the endorsement
+// of the proposal, its submission to the orderer and, unless a commit timeout is given, the wait for its commit.
+func WithEndorsementTimeout(timeout time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.endorsementTimeout = timeout
+	})
+}
+
+// WithEventBufferSize allows to specify the capacity of the event channel returned by an event registration.
+// The event channel is unbuffered by default.
+func WithEventBufferSize(size int) Option {
+	return optionFunc(func(o *options) {
+		if size >= 0 {
+			o.eventBufferSize = size
+		}
+	})
+}
+
+// WithOverflowPolicy allows to specify the behavior of an event registration when its event channel is full.
+// OverflowBlock is used by default.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.overflowPolicy = policy
+	})
+}
+
 // WithOrdererResponseTimeout allows to specify a timeout for orderer response.
 func WithOrdererResponseTimeout(timeout time.Duration) Option {
 	return optionFunc(func(o *options) {
@@ -33,6 +106,79 @@ func WithOrdererResponseTimeout(timeout time.Duration) Option {
 	})
 }
 
+// WithQueryTimeout allows to specify a timeout for the evaluation of a chaincode query.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.queryTimeout = timeout
+	})
+}
+
+// WithRetryPolicy allows to retry the chaincode requests which failed according to the given policy, Submit excepted since
+// an endorsed transaction cannot be broadcast twice. Given to NewClient, it replaces the default retry policy of the
+// resource management requests (e.g. JoinChannel, LifecycleInstallChaincode), the jitter of the policy excepted.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.retryPolicy = &policy
+	})
+}
+
+// WithSeekNewest allows an event registration to start from the most recent block of the channel, this block included.
+func WithSeekNewest() Option {
+	return optionFunc(func(o *options) {
+		o.seekType = seek.Newest
+		o.startBlock = 0
+	})
+}
+
+// WithSeekOldest allows an event registration to replay the events of the channel from its genesis block.
+func WithSeekOldest() Option {
+	return optionFunc(func(o *options) {
+		o.seekType = seek.Oldest
+		o.startBlock = 0
+	})
+}
+
+// WithStartBlock allows an event registration to replay the events of the channel from the given block number.
+func WithStartBlock(blockNumber uint64) Option {
+	return optionFunc(func(o *options) {
+		o.seekType = seek.FromBlock
+		o.startBlock = blockNumber
+	})
+}
+
+// WithSubscriber allows independent subscribers to register for the same chaincode events, each registration being
+// identified by the chaincode ID, the event filter and the subscriber. It also selects the registrations removed by
+// UnregisterChaincodeEvent.
+func WithSubscriber(subscriber string) Option {
+	return optionFunc(func(o *options) {
+		o.subscriber = subscriber
+	})
+}
+
+// WithTargetFilter allows to restrict the peers a request is sent to, among the ones selected by the SDK, to those
+// accepted by the given filter. Target peers (see WithTargetPeers) take precedence over the filter.
+func WithTargetFilter(filter func(Peer) bool) Option {
+	return optionFunc(func(o *options) {
+		o.targetFilter = filter
+	})
+}
+
+// WithTargetOrgs allows to restrict the peers a request is sent to, among the ones selected by the SDK, to those of the
+// given organizations, identified by their MSP ID. Target peers (see WithTargetPeers) take precedence over the organizations.
+func WithTargetOrgs(mspIDs ...string) Option {
+	return optionFunc(func(o *options) {
+		o.targetOrgs = mspIDs
+	})
+}
+
+// WithTargetPeers allows to send a request to the given peers, identified by their name or URL as defined in the
+// configuration, instead of the ones selected by the SDK.
+func WithTargetPeers(urls ...string) Option {
+	return optionFunc(func(o *options) {
+		o.targetPeers = urls
+	})
+}
+
 // WithUserContext allows to specify a user context.
 func WithUserContext(username string) Option {
 	return optionFunc(func(o *options) {
@@ -3,6 +3,8 @@ package fabclient
 import (
 	"testing"
 	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 )
 
 func TestOptionsWithChannelContext(t *testing.T) {
@@ -23,6 +25,17 @@ func TestOptionsWithChannelContext(t *testing.T) {
 	}
 }
 
+func TestOptionsWithCheckpointer(t *testing.T) {
+	opts := &options{}
+	checkpointer := NewInMemoryCheckpointer()
+
+	WithCheckpointer(checkpointer, "subscription").apply(opts)
+
+	if opts.checkpointer != checkpointer || opts.subscriptionID != "subscription" {
+		t.Fail()
+	}
+}
+
 func TestOptionsWithOrdererResponseTimeout(t *testing.T) {
 	opts := &options{
 		ordererResponseTimeout: -1,
@@ -41,6 +54,78 @@ func TestOptionsWithOrdererResponseTimeout(t *testing.T) {
 	}
 }
 
+func TestOptionsWithTimeoutsAndRetryPolicy(t *testing.T) {
+	opts := &options{}
+
+	WithEndorsementTimeout(time.Second).apply(opts)
+	WithCommitTimeout(2 * time.Second).apply(opts)
+	WithQueryTimeout(3 * time.Second).apply(opts)
+	WithRetryPolicy(RetryPolicy{Attempts: 3}).apply(opts)
+
+	if opts.endorsementTimeout != time.Second || opts.commitTimeout != 2*time.Second || opts.queryTimeout != 3*time.Second {
+		t.Fail()
+	}
+
+	if opts.retryPolicy == nil || opts.retryPolicy.Attempts != 3 {
+		t.Fail()
+	}
+}
+
+func TestOptionsWithEventDelivery(t *testing.T) {
+	opts := &options{}
+
+	WithEventBufferSize(16).apply(opts)
+	WithOverflowPolicy(OverflowDropOldest).apply(opts)
+	if opts.eventBufferSize != 16 || opts.overflowPolicy != OverflowDropOldest {
+		t.Fail()
+	}
+
+	WithEventBufferSize(-1).apply(opts)
+	if opts.eventBufferSize != 16 {
+		t.Fail()
+	}
+}
+
+func TestOptionsWithSeek(t *testing.T) {
+	opts := &options{}
+
+	WithStartBlock(42).apply(opts)
+	if opts.seekType != seek.FromBlock || opts.startBlock != 42 {
+		t.Fail()
+	}
+
+	WithSeekOldest().apply(opts)
+	if opts.seekType != seek.Oldest || opts.startBlock != 0 {
+		t.Fail()
+	}
+
+	WithSeekNewest().apply(opts)
+	if opts.seekType != seek.Newest || opts.startBlock != 0 {
+		t.Fail()
+	}
+}
+
+func TestOptionsWithSubscriber(t *testing.T) {
+	opts := &options{}
+
+	WithSubscriber("foo").apply(opts)
+	if opts.subscriber != "foo" {
+		t.Fail()
+	}
+}
+
+func TestOptionsWithTargets(t *testing.T) {
+	opts := &options{}
+
+	WithTargetPeers("peer0.org1.dummy.com", "peer1.org1.dummy.com").apply(opts)
+	WithTargetOrgs("Org1MSP").apply(opts)
+	WithTargetFilter(func(peer Peer) bool { return peer.LedgerHeight > 0 }).apply(opts)
+
+	if len(opts.targetPeers) != 2 || len(opts.targetOrgs) != 1 || opts.targetOrgs[0] != "Org1MSP" || opts.targetFilter == nil {
+		t.Fail()
+	}
+}
+
 func TestOptionsWithUserContext(t *testing.T) {
 	opts := &options{
 		userIdentity: "",
@@ -0,0 +1,222 @@
+package fabclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	channelParticipationPath = "/participation/v1/channels"
+	ordererAdminTimeout      = 30 * time.Second
+)
+
+// OrdererAdmin holds the configuration of the admin endpoint of an orderer (i.e. the osnadmin endpoint), reached over
+// mutual TLS.
+type OrdererAdmin struct {
+	ClientCert string `json:"clientCert" yaml:"clientCert"`
+	ClientKey  string `json:"clientKey" yaml:"clientKey"`
+	Endpoint   string `json:"endpoint" yaml:"endpoint"`
+	TLSCACert  string `json:"tlsCACert" yaml:"tlsCACert"`
+}
+
+// OrdererChannelInfo describes a channel an orderer is a member or a follower of.
+type OrdererChannelInfo struct {
+	Name              string `json:"name"`
+	URL               string `json:"url"`
+	ConsensusRelation string `json:"consensusRelation,omitempty"`
+	Status            string `json:"status,omitempty"`
+	Height            uint64 `json:"height,omitempty"`
+}
+
+// OrdererChannelList is the list of the channels an orderer participates in. The system channel is nil on networks
+// without an ordering system channel.
+type OrdererChannelList struct {
+	SystemChannel *OrdererChannelInfo   `json:"systemChannel"`
+	Channels      []*OrdererChannelInfo `json:"channels"`
+}
+
+// OrdererAdminClient enables to manage the channels an orderer participates in through the channel participation API
+// of its admin endpoint (Fabric 2.3+).
+type OrdererAdminClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOrdererAdminClient returns an OrdererAdminClient instance. The TLS CA certificate, client certificate and client
+// key are read from their paths.
+func NewOrdererAdminClient(config OrdererAdmin) (*OrdererAdminClient, error) {
+	caCert, err := ioutil.ReadFile(config.TLSCACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create orderer admin client: %w", err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to create orderer admin client: no valid certificate found in '%s'", config.TLSCACert)
+	}
+
+	clientCert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create orderer admin client: %w", err)
+	}
+
+	baseURL := config.Endpoint
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	client := &OrdererAdminClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: ordererAdminTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{clientCert},
+					RootCAs:      rootCAs,
+				},
+			},
+		},
+	}
+
+	return client, nil
+}
+
+// JoinChannel joins the orderer to a channel, using the genesis block or the latest config block of the channel.
+func (admin *OrdererAdminClient) JoinChannel(channelID string, configBlock []byte) (*OrdererChannelInfo, error) {
+	return admin.JoinChannelContext(context.Background(), channelID, configBlock)
+}
+
+// JoinChannelContext joins the orderer to a channel, using the genesis block or the latest config block of the channel.
+// The provided context controls the cancellation and deadline of the request.
+func (admin *OrdererAdminClient) JoinChannelContext(ctx context.Context, channelID string, configBlock []byte) (*OrdererChannelInfo, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("config-block", channelID+".block")
+	if err != nil {
+		return nil, fmt.Errorf("failed to join orderer to channel '%s': %w", channelID, err)
+	}
+
+	if _, err := part.Write(configBlock); err != nil {
+		return nil, fmt.Errorf("failed to join orderer to channel '%s': %w", channelID, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to join orderer to channel '%s': %w", channelID, err)
+	}
+
+	channelInfo := &OrdererChannelInfo{}
+	if err := admin.do(ctx, http.MethodPost, channelParticipationPath, writer.FormDataContentType(), body, http.StatusCreated, channelInfo); err != nil {
+		if strings.Contains(err.Error(), _ordererChannelAlreadyJoined) {
+			err = ErrChannelAlreadyExists
+		}
+
+		return nil, fmt.Errorf("failed to join orderer to channel '%s': %w", channelID, err)
+	}
+
+	return channelInfo, nil
+}
+
+// ListChannels lists the channels the orderer participates in.
+func (admin *OrdererAdminClient) ListChannels() (*OrdererChannelList, error) {
+	return admin.ListChannelsContext(context.Background())
+}
+
+// ListChannelsContext lists the channels the orderer participates in. The provided context controls the cancellation
+// and deadline of the request.
+func (admin *OrdererAdminClient) ListChannelsContext(ctx context.Context) (*OrdererChannelList, error) {
+	channelList := &OrdererChannelList{}
+	if err := admin.do(ctx, http.MethodGet, channelParticipationPath, "", nil, http.StatusOK, channelList); err != nil {
+		return nil, fmt.Errorf("failed to list channels of orderer: %w", err)
+	}
+
+	return channelList, nil
+}
+
+// ChannelInfo returns the participation of the orderer in a channel.
+func (admin *OrdererAdminClient) ChannelInfo(channelID string) (*OrdererChannelInfo, error) {
+	return admin.ChannelInfoContext(context.Background(), channelID)
+}
+
+// ChannelInfoContext returns the participation of the orderer in a channel. The provided context controls the
+// cancellation and deadline of the request.
+func (admin *OrdererAdminClient) ChannelInfoContext(ctx context.Context, channelID string) (*OrdererChannelInfo, error) {
+	channelInfo := &OrdererChannelInfo{}
+	if err := admin.do(ctx, http.MethodGet, channelParticipationPath+"/"+url.PathEscape(channelID), "", nil, http.StatusOK, channelInfo); err != nil {
+		return nil, fmt.Errorf("failed to retrieve channel '%s' of orderer: %w", channelID, err)
+	}
+
+	return channelInfo, nil
+}
+
+// RemoveChannel removes the orderer from a channel.
+func (admin *OrdererAdminClient) RemoveChannel(channelID string) error {
+	return admin.RemoveChannelContext(context.Background(), channelID)
+}
+
+// RemoveChannelContext removes the orderer from a channel. The provided context controls the cancellation and deadline
+// of the request.
+func (admin *OrdererAdminClient) RemoveChannelContext(ctx context.Context, channelID string) error {
+	if err := admin.do(ctx, http.MethodDelete, channelParticipationPath+"/"+url.PathEscape(channelID), "", nil, http.StatusNoContent, nil); err != nil {
+		return fmt.Errorf("failed to remove orderer from channel '%s': %w", channelID, err)
+	}
+
+	return nil
+}
+
+func (admin *OrdererAdminClient) do(ctx context.Context, method, path, contentType string, body io.Reader, expectedStatus int, result interface{}) error {
+	request, err := http.NewRequest(method, admin.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := admin.httpClient.Do(request)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != expectedStatus {
+		errorResponse := struct {
+			Error string `json:"error"`
+		}{}
+
+		if err := json.Unmarshal(responseBody, &errorResponse); err != nil || errorResponse.Error == "" {
+			return fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, errorResponse.Error)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(responseBody, result); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
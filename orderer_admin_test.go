@@ -0,0 +1,292 @@
+package fabclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signerCertificate, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signerCertificate, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCertificate, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// newTestOrdererAdminServer starts a stand-in of the channel participation API of an orderer, requiring clients to
+// authenticate with a certificate issued by the given CA.
+func newTestOrdererAdminServer(t *testing.T, ca *testCertificate) *httptest.Server {
+	var mutex sync.Mutex
+	channels := make(map[string]*OrdererChannelInfo)
+
+	writeJSON := func(w http.ResponseWriter, status int, body interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+
+	writeError := func(w http.ResponseWriter, status int, message string) {
+		writeJSON(w, status, map[string]string{"error": message})
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, channelParticipationPath), "/")
+
+		switch {
+		case r.Method == http.MethodPost && name == "":
+			file, header, err := r.FormFile("config-block")
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "cannot read form from request body: "+err.Error())
+				return
+			}
+
+			defer file.Close()
+
+			block, err := ioutil.ReadAll(file)
+			if err != nil || len(block) == 0 {
+				writeError(w, http.StatusBadRequest, "cannot join: config block is empty")
+				return
+			}
+
+			channelID := strings.TrimSuffix(header.Filename, ".block")
+			if _, ok := channels[channelID]; ok {
+				writeError(w, http.StatusMethodNotAllowed, "cannot join: "+_ordererChannelAlreadyJoined)
+				return
+			}
+
+			channels[channelID] = &OrdererChannelInfo{
+				Name:              channelID,
+				URL:               channelParticipationPath + "/" + channelID,
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            1,
+			}
+
+			writeJSON(w, http.StatusCreated, channels[channelID])
+		case r.Method == http.MethodGet && name == "":
+			channelList := &OrdererChannelList{}
+			for _, channel := range channels {
+				channelList.Channels = append(channelList.Channels, &OrdererChannelInfo{Name: channel.Name, URL: channel.URL})
+			}
+
+			writeJSON(w, http.StatusOK, channelList)
+		case r.Method == http.MethodGet:
+			channel, ok := channels[name]
+			if !ok {
+				writeError(w, http.StatusNotFound, "channel does not exist")
+				return
+			}
+
+			writeJSON(w, http.StatusOK, channel)
+		case r.Method == http.MethodDelete:
+			if _, ok := channels[name]; !ok {
+				writeError(w, http.StatusNotFound, "cannot remove: channel does not exist")
+				return
+			}
+
+			delete(channels, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "invalid request method: "+r.Method)
+		}
+	})
+
+	serverCertificate := newTestCertificate(t, "orderer.example.com", ca)
+
+	keyPair, err := tls.X509KeyPair(serverCertificate.certPEM, serverCertificate.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.certificate)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+
+	server.StartTLS()
+
+	return server
+}
+
+func newTestOrdererAdmin(t *testing.T, dir, endpoint string, ca *testCertificate, name string) OrdererAdmin {
+	clientCertificate := newTestCertificate(t, name, ca)
+
+	config := OrdererAdmin{
+		ClientCert: filepath.Join(dir, name+"-cert.pem"),
+		ClientKey:  filepath.Join(dir, name+"-key.pem"),
+		Endpoint:   endpoint,
+		TLSCACert:  filepath.Join(dir, "ca-cert.pem"),
+	}
+
+	for path, content := range map[string][]byte{
+		config.ClientCert: clientCertificate.certPEM,
+		config.ClientKey:  clientCertificate.keyPEM,
+		config.TLSCACert:  ca.certPEM,
+	} {
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return config
+}
+
+func TestOrdererAdminClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ordereradmin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ca := newTestCertificate(t, "tlsca.example.com", nil)
+
+	server := newTestOrdererAdminServer(t, ca)
+	defer server.Close()
+
+	admin, err := NewOrdererAdminClient(newTestOrdererAdmin(t, dir, strings.TrimPrefix(server.URL, "https://"), ca, "admin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	channelInfo, err := admin.JoinChannel("channelall", []byte("genesis block"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if channelInfo.Name != "channelall" || channelInfo.ConsensusRelation != "consenter" || channelInfo.Height != 1 {
+		t.Errorf("unexpected channel info: %+v", channelInfo)
+	}
+
+	if _, err := admin.JoinChannel("channelall", []byte("genesis block")); !errors.Is(err, ErrChannelAlreadyExists) {
+		t.Errorf("should have returned an error, orderer already joined channel 'channelall': %v", err)
+	}
+
+	channelList, err := admin.ListChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(channelList.Channels) != 1 || channelList.Channels[0].Name != "channelall" || channelList.SystemChannel != nil {
+		t.Errorf("unexpected channel list: %+v", channelList)
+	}
+
+	if _, err := admin.ChannelInfo("channelall"); err != nil {
+		t.Error(err)
+	}
+
+	if err := admin.RemoveChannel("channelall"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := admin.ChannelInfo("channelall"); err == nil {
+		t.Error("should have returned an error, orderer removed from channel 'channelall'")
+	}
+
+	if err := admin.RemoveChannel("channelall"); err == nil {
+		t.Error("should have returned an error, orderer removed from channel 'channelall'")
+	}
+}
+
+func TestOrdererAdminClientFailureCases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ordereradmin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ca := newTestCertificate(t, "tlsca.example.com", nil)
+
+	server := newTestOrdererAdminServer(t, ca)
+	defer server.Close()
+
+	if _, err := NewOrdererAdminClient(OrdererAdmin{TLSCACert: "/dummy"}); err == nil {
+		t.Error("should have returned an error, we provided a wrong TLS CA certificate path")
+	}
+
+	// the client certificate is issued by a CA the orderer does not trust
+	untrusted := newTestOrdererAdmin(t, dir, server.URL, newTestCertificate(t, "untrusted.example.com", nil), "untrusted")
+	untrusted.TLSCACert = newTestOrdererAdmin(t, dir, server.URL, ca, "admin").TLSCACert
+
+	admin, err := NewOrdererAdminClient(untrusted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := admin.ListChannels(); err == nil {
+		t.Error("should have returned an error, client certificate is not trusted by the orderer")
+	}
+}
@@ -1,17 +1,20 @@
 package fabclient
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	protomsp "github.com/hyperledger/fabric-protos-go/msp"
 	protopeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	mspprovider "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -21,15 +24,21 @@ import (
 )
 
 type resourceManager interface {
-	saveChannel(channelID, channelConfigPath string) error
-	joinChannel(channelID string) error
-	lifecycleInstallChaincode(chaincode Chaincode) (string, error)
-	lifecycleApproveChaincode(channelID, packageID string, chaincode Chaincode) error
-	lifecycleCheckChaincodeCommitReadiness(channelID string, chaincode Chaincode) (map[string]bool, error)
-	lifecycleCommitChaincode(channelID string, chaincode Chaincode) error
-	isChaincodeInstalled(packageID string) bool
-	isChaincodeApproved(channelID, chaincodeName string, sequence int64) bool
-	isChaincodeCommitted(channelID, chaincodeName string, sequence int64) bool
+	saveChannel(ctx context.Context, channelID, channelConfigPath string) error
+	saveChannelFromTx(ctx context.Context, channelID string, channelTx []byte) error
+	updateAnchorPeers(ctx context.Context, channelID string) error
+	updateChannelConfig(ctx context.Context, channelID string, edit func(config *common.Config) error) error
+	createChannelConfigUpdate(ctx context.Context, channelID string, edit func(config *common.Config) error) ([]byte, error)
+	submitChannelConfigUpdate(ctx context.Context, channelID string, envelope []byte) error
+	joinChannel(ctx context.Context, channelID string) error
+	queryChannels(ctx context.Context) ([]string, error)
+	lifecycleInstallChaincode(ctx context.Context, chaincode Chaincode) (string, error)
+	lifecycleApproveChaincode(ctx context.Context, channelID, packageID string, chaincode Chaincode) error
+	lifecycleCheckChaincodeCommitReadiness(ctx context.Context, channelID string, chaincode Chaincode) (map[string]bool, error)
+	lifecycleCommitChaincode(ctx context.Context, channelID string, chaincode Chaincode) error
+	isChaincodeInstalled(ctx context.Context, packageID string) bool
+	isChaincodeApproved(ctx context.Context, channelID, chaincodeName string, sequence int64) bool
+	isChaincodeCommitted(ctx context.Context, channelID, chaincodeName string, sequence int64) bool
 }
 
 type resourceManagementClient struct {
@@ -41,7 +50,9 @@ type resourceManagementClient struct {
 	withTargetPeersOpt     resmgmt.RequestOption
 }
 
-func newResourceManager(ctx context.ClientProvider, identity mspprovider.SigningIdentity) (resourceManager, error) {
+// newResourceManager returns a resource manager retrying the failed requests according to the given policy, the
+// default retry options of the SDK are used when it is nil.
+func newResourceManager(ctx contextAPI.ClientProvider, identity mspprovider.SigningIdentity, retryPolicy *RetryPolicy) (resourceManager, error) {
 	localContext, err := contextImpl.NewLocal(ctx)
 	if err != nil {
 		return nil, err
@@ -68,12 +79,17 @@ func newResourceManager(ctx context.ClientProvider, identity mspprovider.Signing
 		randomOrderer = os.Getenv("TARGET_ORDERER")
 	}
 
+	retryOpts := retry.DefaultResMgmtOpts
+	if retryPolicy != nil {
+		retryOpts = retryPolicy.retryOpts(retry.ResMgmtDefaultRetryableCodes)
+	}
+
 	rsmClient := &resourceManagementClient{
 		adminIdentity:          identity,
 		client:                 client,
 		peers:                  peers,
 		withOrdererEndpointOpt: resmgmt.WithOrdererEndpoint(randomOrderer),
-		withRetryOpt:           resmgmt.WithRetry(retry.DefaultResMgmtOpts),
+		withRetryOpt:           resmgmt.WithRetry(retryOpts),
 		withTargetPeersOpt:     resmgmt.WithTargets(peers...),
 	}
 
@@ -82,32 +98,176 @@ func newResourceManager(ctx context.ClientProvider, identity mspprovider.Signing
 
 var _ resourceManager = (*resourceManagementClient)(nil)
 
-func (rsm *resourceManagementClient) saveChannel(channelID, channelConfigPath string) error {
-	request := resmgmt.SaveChannelRequest{
+func (rsm *resourceManagementClient) saveChannel(ctx context.Context, channelID, channelConfigPath string) error {
+	return rsm.submitSaveChannelRequest(ctx, resmgmt.SaveChannelRequest{
 		ChannelID:         channelID,
 		ChannelConfigPath: channelConfigPath,
 		SigningIdentities: []mspprovider.SigningIdentity{rsm.adminIdentity},
+	})
+}
+
+func (rsm *resourceManagementClient) saveChannelFromTx(ctx context.Context, channelID string, channelTx []byte) error {
+	return rsm.submitSaveChannelRequest(ctx, resmgmt.SaveChannelRequest{
+		ChannelID:         channelID,
+		ChannelConfig:     bytes.NewReader(channelTx),
+		SigningIdentities: []mspprovider.SigningIdentity{rsm.adminIdentity},
+	})
+}
+
+func (rsm *resourceManagementClient) submitSaveChannelRequest(ctx context.Context, request resmgmt.SaveChannelRequest) error {
+	if _, err := rsm.client.SaveChannel(request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt); err != nil {
+		if strings.Contains(err.Error(), _channelAlreadyExists) {
+			err = ErrChannelAlreadyExists
+		}
+
+		return fmt.Errorf("failed to save channel '%s': %w", request.ChannelID, err)
 	}
 
-	if _, err := rsm.client.SaveChannel(request, rsm.withOrdererEndpointOpt, rsm.withRetryOpt); err != nil {
-		if !strings.Contains(err.Error(), _channelAlreadyExists) {
-			return fmt.Errorf("failed to save channel '%s': %w", channelID, err)
+	return nil
+}
+
+func (rsm *resourceManagementClient) updateAnchorPeers(ctx context.Context, channelID string) error {
+	mspID := rsm.adminIdentity.Identifier().MSPID
+
+	anchorPeers := make([]*AnchorPeer, 0, len(rsm.peers))
+	for _, peer := range rsm.peers {
+		if peer.MSPID() != mspID {
+			continue
+		}
+
+		anchorPeer, err := parseAnchorPeer(peer.URL())
+		if err != nil {
+			return fmt.Errorf("failed to update anchor peers of channel '%s': %w", channelID, err)
 		}
+
+		anchorPeers = append(anchorPeers, anchorPeer)
+	}
+
+	if len(anchorPeers) == 0 {
+		return fmt.Errorf("failed to update anchor peers of channel '%s': no peer found for organization '%s'", channelID, mspID)
+	}
+
+	return rsm.updateChannelConfig(ctx, channelID, func(config *common.Config) error {
+		return setAnchorPeers(config, mspID, anchorPeers)
+	})
+}
+
+func (rsm *resourceManagementClient) updateChannelConfig(ctx context.Context, channelID string, edit func(config *common.Config) error) error {
+	configUpdate, err := rsm.computeChannelConfigUpdate(ctx, channelID, edit)
+	if errors.Is(err, errNoConfigDifferences) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update config of channel '%s': %w", channelID, err)
+	}
+
+	envelope, err := newConfigUpdateEnvelope(configUpdate)
+	if err != nil {
+		return fmt.Errorf("failed to update config of channel '%s': %w", channelID, err)
+	}
+
+	request := resmgmt.SaveChannelRequest{
+		ChannelID:         channelID,
+		ChannelConfig:     bytes.NewReader(envelope),
+		SigningIdentities: []mspprovider.SigningIdentity{rsm.adminIdentity},
+	}
+
+	if _, err := rsm.client.SaveChannel(request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt); err != nil {
+		return fmt.Errorf("failed to update config of channel '%s': %w", channelID, err)
+	}
+
+	return nil
+}
+
+func (rsm *resourceManagementClient) createChannelConfigUpdate(ctx context.Context, channelID string, edit func(config *common.Config) error) ([]byte, error) {
+	configUpdate, err := rsm.computeChannelConfigUpdate(ctx, channelID, edit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config update of channel '%s': %w", channelID, err)
+	}
+
+	envelope, err := newConfigUpdateEnvelope(configUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config update of channel '%s': %w", channelID, err)
+	}
+
+	return envelope, nil
+}
+
+func (rsm *resourceManagementClient) submitChannelConfigUpdate(ctx context.Context, channelID string, envelope []byte) error {
+	configUpdateEnvelope, err := decodeConfigUpdateEnvelope(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to submit config update of channel '%s': %w", channelID, err)
+	}
+
+	if len(configUpdateEnvelope.Signatures) == 0 {
+		return fmt.Errorf("failed to submit config update of channel '%s': config update is not signed", channelID)
+	}
+
+	request := resmgmt.SaveChannelRequest{
+		ChannelID:     channelID,
+		ChannelConfig: bytes.NewReader(envelope),
+	}
+
+	withSignaturesOpt := resmgmt.WithConfigSignatures(configUpdateEnvelope.Signatures...)
+	if _, err := rsm.client.SaveChannel(request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt, withSignaturesOpt); err != nil {
+		return fmt.Errorf("failed to submit config update of channel '%s': %w", channelID, err)
 	}
 
 	return nil
 }
 
-func (rsm *resourceManagementClient) joinChannel(channelID string) error {
-	err := rsm.client.JoinChannel(channelID, rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
-	if err != nil && !strings.Contains(err.Error(), _channelAlreadyJoined) {
+func (rsm *resourceManagementClient) computeChannelConfigUpdate(ctx context.Context, channelID string, edit func(config *common.Config) error) (*common.ConfigUpdate, error) {
+	block, err := rsm.client.QueryConfigBlockFromOrderer(channelID, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := extractConfigFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	if err := edit(updated); err != nil {
+		return nil, err
+	}
+
+	return computeConfigUpdate(channelID, original, updated)
+}
+
+func (rsm *resourceManagementClient) joinChannel(ctx context.Context, channelID string) error {
+	err := rsm.client.JoinChannel(channelID, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
+	if err != nil {
+		if strings.Contains(err.Error(), _channelAlreadyJoined) {
+			err = ErrChannelAlreadyExists
+		}
+
 		return fmt.Errorf("failed to join channel '%s': %w", channelID, err)
 	}
 
 	return nil
 }
 
-func (rsm *resourceManagementClient) lifecycleInstallChaincode(chaincode Chaincode) (string, error) {
+func (rsm *resourceManagementClient) queryChannels(ctx context.Context) ([]string, error) {
+	channels := make([]string, 0)
+	for _, peer := range rsm.peers {
+		response, err := rsm.client.QueryChannels(resmgmt.WithParentContext(ctx), resmgmt.WithTargets(peer), rsm.withRetryOpt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query channels of peer '%s': %w", peer.URL(), err)
+		}
+
+		for _, channel := range response.GetChannels() {
+			if !containsString(channels, channel.GetChannelId()) {
+				channels = append(channels, channel.GetChannelId())
+			}
+		}
+	}
+
+	return channels, nil
+}
+
+func (rsm *resourceManagementClient) lifecycleInstallChaincode(ctx context.Context, chaincode Chaincode) (string, error) {
 	label := chaincode.Name + "_" + chaincode.Version
 
 	descriptor := &lifecycle.Descriptor{
@@ -128,7 +288,7 @@ func (rsm *resourceManagementClient) lifecycleInstallChaincode(chaincode Chainco
 		Package: chaincodePackage,
 	}
 
-	res, err := rsm.client.LifecycleInstallCC(request, rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
+	res, err := rsm.client.LifecycleInstallCC(request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
 	if err != nil {
 		return "", err
 	}
@@ -150,7 +310,7 @@ func (rsm *resourceManagementClient) lifecycleInstallChaincode(chaincode Chainco
 	return packageID, err
 }
 
-func (rsm *resourceManagementClient) lifecycleApproveChaincode(channelID, packageID string, chaincode Chaincode) error {
+func (rsm *resourceManagementClient) lifecycleApproveChaincode(ctx context.Context, channelID, packageID string, chaincode Chaincode) error {
 	request := resmgmt.LifecycleApproveCCRequest{
 		Name:              chaincode.Name,
 		Version:           chaincode.Version,
@@ -171,7 +331,7 @@ func (rsm *resourceManagementClient) lifecycleApproveChaincode(channelID, packag
 		request.CollectionConfig = collectionsConfig
 	}
 
-	txID, err := rsm.client.LifecycleApproveCC(channelID, request, rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
+	txID, err := rsm.client.LifecycleApproveCC(channelID, request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt, rsm.withTargetPeersOpt)
 	if err != nil {
 		return fmt.Errorf("failed to approve chaincode '%s': %w", chaincode.Name, err)
 	}
@@ -183,7 +343,7 @@ func (rsm *resourceManagementClient) lifecycleApproveChaincode(channelID, packag
 	return nil
 }
 
-func (rsm *resourceManagementClient) lifecycleCheckChaincodeCommitReadiness(channelID string, chaincode Chaincode) (map[string]bool, error) {
+func (rsm *resourceManagementClient) lifecycleCheckChaincodeCommitReadiness(ctx context.Context, channelID string, chaincode Chaincode) (map[string]bool, error) {
 	request := resmgmt.LifecycleCheckCCCommitReadinessRequest{
 		Name:              chaincode.Name,
 		Version:           chaincode.Version,
@@ -203,7 +363,7 @@ func (rsm *resourceManagementClient) lifecycleCheckChaincodeCommitReadiness(chan
 		request.CollectionConfig = collectionsConfig
 	}
 
-	response, err := rsm.client.LifecycleCheckCCCommitReadiness(channelID, request, rsm.withRetryOpt, rsm.withTargetPeersOpt)
+	response, err := rsm.client.LifecycleCheckCCCommitReadiness(channelID, request, resmgmt.WithParentContext(ctx), rsm.withRetryOpt, rsm.withTargetPeersOpt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check the commit readiness for chaincode '%s': %w", chaincode.Name, err)
 	}
@@ -211,7 +371,7 @@ func (rsm *resourceManagementClient) lifecycleCheckChaincodeCommitReadiness(chan
 	return response.Approvals, nil
 }
 
-func (rsm *resourceManagementClient) lifecycleCommitChaincode(channelID string, chaincode Chaincode) error {
+func (rsm *resourceManagementClient) lifecycleCommitChaincode(ctx context.Context, channelID string, chaincode Chaincode) error {
 	request := resmgmt.LifecycleCommitCCRequest{
 		Name:              chaincode.Name,
 		Version:           chaincode.Version,
@@ -231,7 +391,7 @@ func (rsm *resourceManagementClient) lifecycleCommitChaincode(channelID string,
 		request.CollectionConfig = collectionsConfig
 	}
 
-	txID, err := rsm.client.LifecycleCommitCC(channelID, request, rsm.withOrdererEndpointOpt, rsm.withRetryOpt)
+	txID, err := rsm.client.LifecycleCommitCC(channelID, request, resmgmt.WithParentContext(ctx), rsm.withOrdererEndpointOpt, rsm.withRetryOpt)
 	if err != nil {
 		return fmt.Errorf("failed to commit chaincode '%s': %w", chaincode.Name, err)
 	}
@@ -243,7 +403,7 @@ func (rsm *resourceManagementClient) lifecycleCommitChaincode(channelID string,
 	return nil
 }
 
-func (rsm *resourceManagementClient) isChaincodeInstalled(packageID string) bool {
+func (rsm *resourceManagementClient) isChaincodeInstalled(ctx context.Context, packageID string) bool {
 	count := 0
 	success := true
 	checkChan := make(chan bool)
@@ -253,7 +413,7 @@ func (rsm *resourceManagementClient) isChaincodeInstalled(packageID string) bool
 		peer := p
 
 		go func() {
-			response, err := rsm.client.LifecycleQueryInstalledCC(resmgmt.WithTargets(peer))
+			response, err := rsm.client.LifecycleQueryInstalledCC(resmgmt.WithParentContext(ctx), resmgmt.WithTargets(peer))
 			if err != nil {
 				checkChan <- false
 				return
@@ -289,7 +449,7 @@ func (rsm *resourceManagementClient) isChaincodeInstalled(packageID string) bool
 	}
 }
 
-func (rsm *resourceManagementClient) isChaincodeApproved(channelID, chaincodeName string, sequence int64) bool {
+func (rsm *resourceManagementClient) isChaincodeApproved(ctx context.Context, channelID, chaincodeName string, sequence int64) bool {
 	count := 0
 	success := true
 	checkChan := make(chan bool)
@@ -304,7 +464,7 @@ func (rsm *resourceManagementClient) isChaincodeApproved(channelID, chaincodeNam
 		peer := p
 
 		go func() {
-			response, err := rsm.client.LifecycleQueryApprovedCC(channelID, request, resmgmt.WithTargets(peer))
+			response, err := rsm.client.LifecycleQueryApprovedCC(channelID, request, resmgmt.WithParentContext(ctx), resmgmt.WithTargets(peer))
 			if err != nil {
 				checkChan <- false
 				return
@@ -332,7 +492,7 @@ func (rsm *resourceManagementClient) isChaincodeApproved(channelID, chaincodeNam
 	}
 }
 
-func (rsm *resourceManagementClient) isChaincodeCommitted(channelID, chaincodeName string, sequence int64) bool {
+func (rsm *resourceManagementClient) isChaincodeCommitted(ctx context.Context, channelID, chaincodeName string, sequence int64) bool {
 	count := 0
 	success := true
 	checkChan := make(chan bool)
@@ -346,7 +506,7 @@ func (rsm *resourceManagementClient) isChaincodeCommitted(channelID, chaincodeNa
 		peer := p
 
 		go func() {
-			response, err := rsm.client.LifecycleQueryCommittedCC(channelID, request, resmgmt.WithTargets(peer))
+			response, err := rsm.client.LifecycleQueryCommittedCC(channelID, request, resmgmt.WithParentContext(ctx), resmgmt.WithTargets(peer))
 			if err != nil {
 				checkChan <- false
 				return
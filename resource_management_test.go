@@ -1,7 +1,10 @@
 package fabclient
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 func org1CreateUpdateAndJoinChannel(t *testing.T, client *Client) {
@@ -22,12 +25,20 @@ func org1CreateUpdateAndJoinChannel(t *testing.T, client *Client) {
 	if err := client.JoinChannel(channel.Name); err != nil {
 		t.Fatal(err)
 	}
+
+	if err := client.UpdateAnchorPeersFromConnectionProfile(channel.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.UpdateAnchorPeersFromConnectionProfile(channel.Name); err != nil {
+		t.Errorf("anchor peers are already up to date, should not have returned an error: %v", err)
+	}
 }
 
 func org2UpdateAndJoinChannel(t *testing.T, client *Client) {
 	channel := client.Config().Channels[0]
 
-	if err := client.SaveChannel(channel.Name, channel.AnchorPeerConfigPath); err != nil {
+	if err := client.UpdateAnchorPeers(channel.Name); err != nil {
 		t.Fatal(err)
 	}
 
@@ -36,6 +47,124 @@ func org2UpdateAndJoinChannel(t *testing.T, client *Client) {
 	}
 }
 
+func queryAndAttachChannels(t *testing.T, client *Client) {
+	channel := client.Config().Channels[0]
+
+	channels, err := client.QueryChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !containsString(channels, channel.Name) {
+		t.Errorf("peers should have joined channel '%s', got %v", channel.Name, channels)
+	}
+
+	if err := client.AttachChannel(channel.Name); err != nil {
+		t.Errorf("channel '%s' already attached, should not have returned an error: %v", channel.Name, err)
+	}
+
+	attachedClient, err := NewClient(client.Config(), WithAutoAttachChannels())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer attachedClient.Close()
+
+	if handler, err := attachedClient.selectChannelHandler(WithChannelContext(channel.Name)); err != nil || handler == nil {
+		t.Errorf("channel '%s' should have been attached when creating the client: %v", channel.Name, err)
+	}
+
+	if _, err := attachedClient.QueryInfo(WithChannelContext(channel.Name)); err != nil {
+		t.Error(err)
+	}
+}
+
+func updateChannelConfig(t *testing.T, client *Client) {
+	channel := client.Config().Channels[0]
+
+	if err := client.UpdateChannelConfig(channel.Name, func(editor *ChannelConfigEditor) error { return nil }); err != nil {
+		t.Errorf("channel config left unchanged, should not have returned an error: %v", err)
+	}
+
+	if err := client.UpdateChannelConfig(channel.Name, func(editor *ChannelConfigEditor) error {
+		return editor.SetPolicy("Dummy", "Admins", &Policy{Type: "IMPLICIT_META", Rule: "ANY Admins"})
+	}); err == nil {
+		t.Error("should have returned an error, group 'Dummy' does not exist")
+	}
+
+	if err := client.UpdateChannelConfig(channel.Name, func(editor *ChannelConfigEditor) error {
+		return editor.AddOrganization(&OrganizationConfig{MSPID: client.Config().Organization})
+	}); err == nil {
+		t.Error("should have returned an error, organization has no root certificate")
+	}
+
+	if err := client.UpdateChannelConfig(channel.Name, func(editor *ChannelConfigEditor) error {
+		return editor.SetBatchTimeout(0)
+	}); err == nil {
+		t.Error("should have returned an error, batch timeout must be positive")
+	}
+}
+
+func multiPartyChannelConfigUpdate(t *testing.T, org1client, org2client *Client) {
+	channel := org1client.Config().Channels[0]
+	resource := "fabric-go-client/MultiPartyUpdate"
+
+	envelope, err := org1client.CreateChannelConfigUpdate(channel.Name, func(editor *ChannelConfigEditor) error {
+		return editor.SetACL(resource, "/Channel/Application/Readers")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := org1client.SubmitChannelConfigUpdate(channel.Name, envelope); err == nil {
+		t.Error("should have returned an error, config update is not signed")
+	}
+
+	org1MSPID := org1client.resourceManager.(*resourceManagementClient).adminIdentity.Identifier().MSPID
+	org1Signed, err := SignChannelConfigUpdate(envelope, org1MSPID, org1client.Config().Identities.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org2MSPID := org2client.resourceManager.(*resourceManagementClient).adminIdentity.Identifier().MSPID
+	org2Signed, err := SignChannelConfigUpdate(envelope, org2MSPID, org2client.Config().Identities.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeChannelConfigUpdateSignatures(org1Signed, org2Signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := org2client.SubmitChannelConfigUpdate(channel.Name, merged); err != nil {
+		t.Fatal(err)
+	}
+
+	// the config block may not be committed by the peers yet
+	timeout := time.After(5 * time.Second)
+	for {
+		config, err := org1client.QueryChannelConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.Application.ACLs[resource] == "/Channel/Application/Readers" {
+			break
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("ACL '%s' should have been set by the config update", resource)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	if _, err := org1client.CreateChannelConfigUpdate(channel.Name, func(editor *ChannelConfigEditor) error { return nil }); err == nil {
+		t.Error("should have returned an error, channel config left unchanged")
+	}
+}
+
 func channelManagementFailureCases(t *testing.T, client *Client) {
 	channel := client.Config().Channels[0]
 	channel.Name = "dummy"
@@ -48,6 +177,26 @@ func channelManagementFailureCases(t *testing.T, client *Client) {
 	if err := client.JoinChannel(channel.Name); err == nil {
 		t.Error("should have returned an error, channel 'dummy' does not exist")
 	}
+
+	if err := client.SaveChannelFromProfile(channel.Name); err == nil {
+		t.Error("should have returned an error, no profile configured for channel 'dummy'")
+	}
+
+	if err := client.BootstrapChannel(channel.Name); err == nil {
+		t.Error("should have returned an error, no profile configured for channel 'dummy'")
+	}
+
+	if err := client.UpdateAnchorPeers(channel.Name); err == nil {
+		t.Error("should have returned an error, no anchor peer config path configured for channel 'dummy'")
+	}
+
+	if err := client.UpdateAnchorPeersFromConnectionProfile(channel.Name); err == nil {
+		t.Error("should have returned an error, channel 'dummy' does not exist")
+	}
+
+	if err := client.UpdateChannelConfig(channel.Name, func(editor *ChannelConfigEditor) error { return nil }); err == nil {
+		t.Error("should have returned an error, channel 'dummy' does not exist")
+	}
 }
 
 func org1InstallAndApproveChaincodeContractAPI(t *testing.T, client *Client) {
@@ -152,4 +301,15 @@ func chaincodeManagementFailureCases(t *testing.T, client *Client) {
 	if client.IsChaincodeCommitted(channel.Name, chaincode.Name, 1) {
 		t.Errorf("chaincode '%s' should not be committed on channel '%s'", chaincode.Name, channel.Name)
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.LifecycleCommitChaincodeContext(ctx, channel.Name, client.Config().Chaincodes[0]); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v when committing chaincode with a canceled context but got %v", context.Canceled, err)
+	}
+
+	if client.IsChaincodeCommittedContext(ctx, channel.Name, client.Config().Chaincodes[0].Name, 1) {
+		t.Error("should have returned false when checking chaincode commit with a canceled context")
+	}
 }
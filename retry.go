@@ -0,0 +1,147 @@
+package fabclient
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+// RetryableErrors is a set of classes of errors which are retried by a RetryPolicy.
+type RetryableErrors uint
+
+const (
+	// RetryTimeouts retries the requests which timed out.
+	RetryTimeouts RetryableErrors = 1 << iota
+	// RetryUnavailable retries the requests which failed because a peer, an orderer or the discovery service could not
+	// be reached or was unavailable.
+	RetryUnavailable
+	// RetryEndorsementMismatch retries the transactions whose proposal responses do not match.
+	RetryEndorsementMismatch
+	// RetryReadConflicts retries the transactions invalidated because of a read conflict (MVCC_READ_CONFLICT,
+	// PHANTOM_READ_CONFLICT).
+	RetryReadConflicts
+	// RetryTransientErrors retries all the errors considered as retryable by IsRetryable.
+	RetryTransientErrors = RetryTimeouts | RetryUnavailable | RetryEndorsementMismatch | RetryReadConflicts
+)
+
+var retryableCodes = map[RetryableErrors]map[status.Group][]status.Code{
+	RetryTimeouts: {
+		status.ClientStatus:         {status.Timeout},
+		status.EndorserClientStatus: {status.Timeout},
+		status.OrdererClientStatus:  {status.Timeout},
+		status.GRPCTransportStatus:  {status.Code(grpcCodes.DeadlineExceeded)},
+	},
+	RetryUnavailable: {
+		status.ClientStatus:          {status.ConnectionFailed, status.NoPeersFound, status.QueryEndorsers, status.GenericTransient},
+		status.EndorserClientStatus:  {status.ConnectionFailed, status.NoPeersFound, status.QueryEndorsers, status.GenericTransient},
+		status.OrdererClientStatus:   {status.ConnectionFailed, status.NoPeersFound, status.QueryEndorsers, status.GenericTransient},
+		status.GRPCTransportStatus:   {status.Code(grpcCodes.Unavailable), status.Code(grpcCodes.ResourceExhausted)},
+		status.EndorserServerStatus:  {status.Code(common.Status_SERVICE_UNAVAILABLE)},
+		status.OrdererServerStatus:   {status.Code(common.Status_SERVICE_UNAVAILABLE)},
+		status.DiscoveryServerStatus: {status.QueryEndorsers},
+	},
+	RetryEndorsementMismatch: {
+		status.ClientStatus:         {status.EndorsementMismatch},
+		status.EndorserClientStatus: {status.EndorsementMismatch},
+		status.OrdererClientStatus:  {status.EndorsementMismatch},
+	},
+	RetryReadConflicts: {
+		status.EventServerStatus: {
+			status.Code(peer.TxValidationCode_MVCC_READ_CONFLICT),
+			status.Code(peer.TxValidationCode_PHANTOM_READ_CONFLICT),
+		},
+	},
+}
+
+// codes returns the status codes, mapped by group, of the errors of the given classes.
+func (r RetryableErrors) codes() map[status.Group][]status.Code {
+	codes := make(map[status.Group][]status.Code)
+
+	for class, classCodes := range retryableCodes {
+		if r&class == 0 {
+			continue
+		}
+
+		for group, groupCodes := range classCodes {
+			codes[group] = append(codes[group], groupCodes...)
+		}
+	}
+
+	return codes
+}
+
+// RetryPolicy describes how a failed request is retried. The backoff before the first retry is InitialBackoff, it is
+// then multiplied by BackoffFactor for each consecutive retry without exceeding MaxBackoff. A random delay, up to Jitter,
+// is added to each backoff of a chaincode request only: the SDK provides no hook to delay the retries of the resource
+// management requests, which ignore Jitter.
+// RetryOn selects the errors which are retried, the defaults of the SDK are used when it is zero.
+type RetryPolicy struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	BackoffFactor  float64
+	Jitter         time.Duration
+	RetryOn        RetryableErrors
+}
+
+// retryOpts converts the policy into retry options of the SDK, defaultCodes being used when no class of errors has been
+// selected.
+func (p *RetryPolicy) retryOpts(defaultCodes map[status.Group][]status.Code) retry.Opts {
+	opts := retry.Opts{
+		Attempts:       p.Attempts,
+		InitialBackoff: p.InitialBackoff,
+		MaxBackoff:     p.MaxBackoff,
+		BackoffFactor:  p.BackoffFactor,
+		RetryableCodes: defaultCodes,
+	}
+
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+
+	if opts.BackoffFactor < 1 {
+		opts.BackoffFactor = 1
+	}
+
+	if p.RetryOn != 0 {
+		opts.RetryableCodes = p.RetryOn.codes()
+	}
+
+	return opts
+}
+
+// beforeRetry returns the function adding the jitter to the backoff, nil if the policy has no jitter. The jitter is cut
+// short once ctx is done, the function then returns the error of the context.
+func (p *RetryPolicy) beforeRetry(ctx context.Context) func(error) error {
+	if p.Jitter <= 0 {
+		return nil
+	}
+
+	return func(error) error {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(p.Jitter))))
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func isRetryableCode(codes map[status.Group][]status.Code, s *status.Status) bool {
+	for _, code := range codes[s.Group] {
+		if code == status.Code(s.Code) {
+			return true
+		}
+	}
+
+	return false
+}
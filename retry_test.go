@@ -0,0 +1,77 @@
+package fabclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	policy := &RetryPolicy{
+		Attempts:       3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+
+	opts := policy.retryOpts(retry.ChannelClientRetryableCodes)
+	if opts.Attempts != 3 || opts.InitialBackoff != time.Second || opts.MaxBackoff != time.Second || opts.BackoffFactor != 1 {
+		t.Errorf("unexpected retry options: %+v", opts)
+	}
+
+	if len(opts.RetryableCodes) != len(retry.ChannelClientRetryableCodes) {
+		t.Errorf("should have used the default retryable codes: %v", opts.RetryableCodes)
+	}
+
+	if policy.beforeRetry(context.Background()) != nil {
+		t.Error("should not have returned a function, the policy has no jitter")
+	}
+
+	policy.Jitter = time.Millisecond
+	policy.RetryOn = RetryReadConflicts | RetryTimeouts
+
+	if beforeRetry := policy.beforeRetry(context.Background()); beforeRetry == nil {
+		t.Error("should have returned a function adding the jitter")
+	} else if err := beforeRetry(nil); err != nil {
+		t.Errorf("should have waited for the jitter, got: %v", err)
+	}
+
+	// the jitter is cut short once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	jitteredPolicy := &RetryPolicy{Jitter: time.Hour}
+	if err := jitteredPolicy.beforeRetry(ctx)(nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("should have returned %v but got %v", context.Canceled, err)
+	}
+
+	opts = policy.retryOpts(retry.ChannelClientRetryableCodes)
+
+	testCases := []struct {
+		status    *status.Status
+		retryable bool
+	}{
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_MVCC_READ_CONFLICT), "invalid transaction", nil), true},
+		{status.New(status.EventServerStatus, int32(peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "invalid transaction", nil), false},
+		{status.New(status.ClientStatus, status.Timeout.ToInt32(), "request timed out", nil), true},
+		{status.New(status.GRPCTransportStatus, int32(grpcCodes.DeadlineExceeded), "deadline exceeded", nil), true},
+		{status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "connection refused", nil), false},
+		{status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "mismatch", nil), false},
+	}
+
+	for _, testCase := range testCases {
+		if retryable := isRetryableCode(opts.RetryableCodes, testCase.status); retryable != testCase.retryable {
+			t.Errorf("expected %v to be retryable: %t, got %t", testCase.status, testCase.retryable, retryable)
+		}
+
+		// the retry handler of the SDK must agree with the policy
+		if required := retry.New(retry.Opts{Attempts: 1, RetryableCodes: opts.RetryableCodes}).Required(testCase.status); required != testCase.retryable {
+			t.Errorf("expected %v to be retried: %t, got %t", testCase.status, testCase.retryable, required)
+		}
+	}
+}
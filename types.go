@@ -1,5 +1,7 @@
 package fabclient
 
+import "github.com/hyperledger/fabric-protos-go/peer"
+
 // BlockData holds the transactions.
 type BlockData struct {
 	Data [][]byte
@@ -81,9 +83,43 @@ type ChaincodeRequest struct {
 
 // Channel describes a channel configuration.
 type Channel struct {
-	AnchorPeerConfigPath string `json:"anchorPeerConfigPath,omitempty" yaml:"anchorPeerConfigPath,omitempty"`
-	ConfigPath           string `json:"configPath" yaml:"configPath"`
-	Name                 string `json:"name" yaml:"name"`
+	AnchorPeerConfigPath string          `json:"anchorPeerConfigPath,omitempty" yaml:"anchorPeerConfigPath,omitempty"`
+	ConfigPath           string          `json:"configPath" yaml:"configPath"`
+	Name                 string          `json:"name" yaml:"name"`
+	Profile              *ChannelProfile `json:"profile,omitempty" yaml:"profile,omitempty"`
+}
+
+// EndorsedTransaction is a transaction proposal endorsed by the peers, as returned by Endorse. Response holds the
+// results of the simulation so that they can be checked before submitting the transaction with Submit.
+// Proposal and Endorsements hold the marshaled proposal and proposal responses, the transaction can thus be persisted and
+// submitted later on.
+type EndorsedTransaction struct {
+	TransactionID string
+	ChannelID     string
+	Response      *TransactionResponse
+	Proposal      []byte
+	Endorsements  []Endorsement
+}
+
+// Endorsement holds the marshaled proposal response of a peer along with the URL of the latter.
+type Endorsement struct {
+	Endorser         string
+	ProposalResponse []byte
+}
+
+// FilteredBlock contains the filtered transactions of a block.
+type FilteredBlock struct {
+	ChannelID            string
+	Number               uint64
+	FilteredTransactions []*FilteredTransaction
+}
+
+// FilteredTransaction contains the transaction ID, its type, its validation code as well as the chaincode events it emitted.
+type FilteredTransaction struct {
+	TxID             string
+	Type             string
+	TxValidationCode TxValidationCode
+	ChaincodeEvents  []*ChaincodeEvent
 }
 
 // Identity holds crypto material for creating a signing identity.
@@ -93,9 +129,60 @@ type Identity struct {
 	Username    string `json:"username" yaml:"username"`
 }
 
+// Peer describes a peer a request may be sent to (see WithTargetFilter). LedgerHeight is only known when the peer has
+// been discovered, it is zero otherwise.
+type Peer struct {
+	URL          string
+	MSPID        string
+	LedgerHeight uint64
+}
+
+// ProcessedTransaction is a transaction committed on the ledger along with its validation code and the number of the block
+// which contains it.
+type ProcessedTransaction struct {
+	Transaction    *Transaction
+	ValidationCode TxValidationCode
+	BlockNumber    uint64
+}
+
+// ProposalResponse is the response of an endorsing peer to a transaction proposal.
+type ProposalResponse struct {
+	Endorser        string
+	Status          int32
+	ChaincodeStatus int32
+	Message         string
+	Payload         []byte
+}
+
 // TransactionResponse  contains response parameters for query and execute an invocation transaction.
+// The validation code and the number of the block the transaction has been committed in are only set by Invoke.
+// The read/write sets and the chaincode event are the ones simulated by the endorsing peers.
 type TransactionResponse struct {
-	Payload       []byte
-	Status        int32
-	TransactionID string
+	Payload           []byte
+	Status            int32
+	TransactionID     string
+	TxValidationCode  TxValidationCode
+	BlockNumber       uint64
+	ProposalResponses []*ProposalResponse
+	ReadWriteSets     []*NamespaceReadWriteSet
+	Event             *ChaincodeEvent
+}
+
+// TxStatusEvent contains the data for a transaction status event.
+type TxStatusEvent struct {
+	TxID             string
+	TxValidationCode TxValidationCode
+	BlockNumber      uint64
+	SourceURL        string
+}
+
+// TxValidationCode is the code set by the peers when validating a transaction.
+type TxValidationCode int32
+
+// TxValidationCodeValid is the validation code of a valid transaction.
+const TxValidationCodeValid = TxValidationCode(peer.TxValidationCode_VALID)
+
+// String returns the name of the validation code, as defined by Fabric (i.e. "VALID", "MVCC_READ_CONFLICT").
+func (code TxValidationCode) String() string {
+	return peer.TxValidationCode(code).String()
 }
@@ -1,5 +1,7 @@
 package fabclient
 
+import "context"
+
 func convertArrayOfStringsToArrayOfByteArrays(args []string) [][]byte {
 	res := make([][]byte, 0, len(args))
 	for _, arg := range args {
@@ -7,3 +9,46 @@ func convertArrayOfStringsToArrayOfByteArrays(args []string) [][]byte {
 	}
 	return res
 }
+
+// contextError reports the error of the context once it is done, while keeping the error the request failed with so that
+// the typed errors of the request can still be reached with errors.As.
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return &requestContextError{ctxErr: ctxErr, err: err}
+	}
+
+	return err
+}
+
+// requestContextError is the error of a request whose context is done, it matches the error of the context with
+// errors.Is and unwraps to the error of the request.
+type requestContextError struct {
+	ctxErr error
+	err    error
+}
+
+func (e *requestContextError) Error() string {
+	return e.ctxErr.Error() + ": " + e.err.Error()
+}
+
+func (e *requestContextError) Is(target error) bool {
+	return target == e.ctxErr
+}
+
+func (e *requestContextError) Unwrap() error {
+	return e.err
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,8 @@ package fabclient
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -24,3 +26,41 @@ func TestConvertArrayOfStringsToArrayOfByteArrays(t *testing.T) {
 		}
 	}
 }
+
+func TestContextError(t *testing.T) {
+	witness := errors.New("witness")
+
+	if err := contextError(context.Background(), nil); err != nil {
+		t.Errorf("should have returned nil but got %v", err)
+	}
+
+	if err := contextError(context.Background(), witness); err != witness {
+		t.Errorf("should have returned %v but got %v", witness, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := contextError(ctx, nil); err != nil {
+		t.Errorf("should have returned nil but got %v", err)
+	}
+
+	if err := contextError(ctx, witness); !errors.Is(err, context.Canceled) || !errors.Is(err, witness) {
+		t.Errorf("should have returned an error matching both %v and %v but got %v", context.Canceled, witness, err)
+	}
+
+	var endorsementError *EndorsementError
+	if err := contextError(ctx, &EndorsementError{err: errors.New("endorsement failed")}); !errors.As(err, &endorsementError) || IsRetryable(err) {
+		t.Errorf("should have kept the endorsement error and not be retryable, got %v", err)
+	}
+}
+
+func TestContainsString(t *testing.T) {
+	if !containsString([]string{"channelall", "channel1"}, "channel1") {
+		t.Error("should contain 'channel1'")
+	}
+
+	if containsString([]string{"channelall"}, "dummy") || containsString(nil, "dummy") {
+		t.Error("should not contain 'dummy'")
+	}
+}